@@ -0,0 +1,148 @@
+package drawdown
+
+// A Builder assembles a DrawScenario step by step, as an alternative to WithComponents.
+// Sources are registered as they are added, tax accounts are tied to them when they are added,
+// and tax regimes are registered as accounts are created for them.
+// A scenario built within DefineWith can be cloned.
+//
+//	s, err := DefineWith(func(b *Builder) {
+//		incomeTax := b.TaxWith("Income Tax", incomeTaxRegime)
+//		pension := b.AddSource(NewPensionAccount("Pension", 500000, &b.Rates().InvestmentGrowthRate), incomeTax)
+//		b.DrawFrom(pension).PayTaxFrom(pension)
+//	})
+type Builder struct {
+	s *DrawScenario
+}
+
+// NewBuilder returns a builder for an empty scenario.
+func NewBuilder() *Builder {
+	return &Builder{s: &DrawScenario{TaxAccounts: map[*Source]*TaxAccount{}}}
+}
+
+// DefineWith builds a scenario by calling define with a new Builder and validating the result, as Build does.
+// Like Define, it remembers define so that the scenario can be cloned:
+// define must create new sources, tax accounts and variables each time it is called.
+func DefineWith(define func(b *Builder)) (*DrawScenario, error) {
+	build := func() *Builder {
+		b := NewBuilder()
+		define(b)
+		return b
+	}
+	s, err := build().Build()
+	if err != nil {
+		return nil, err
+	}
+	s.define = func() *DrawScenario { return build().s }
+	return s, nil
+}
+
+// Rates returns the rates of the scenario being built, so that sources can be linked to them.
+func (b *Builder) Rates() *DrawRates {
+	return &b.s.Rates
+}
+
+// TaxWith returns a new tax account under the given regime, registering the regime
+// so that its bands rise each year.
+func (b *Builder) TaxWith(name string, regime TaxRegime) *TaxAccount {
+	b.register(regime)
+	return NewTaxAccount(name, regime)
+}
+
+// NIWith returns the given National Insurance regime for earnings, registering its bands
+// so that they rise each year.
+func (b *Builder) NIWith(regime NIRegime) *NIRegime {
+	b.register(regime.TaxRegime)
+	return &regime
+}
+
+// register adds the regime to the scenario's tax regimes unless it is already there.
+func (b *Builder) register(regime TaxRegime) {
+	for _, tr := range b.s.TaxRegimes {
+		if tr.same(regime) {
+			return
+		}
+	}
+	b.s.TaxRegimes = append(b.s.TaxRegimes, &regime)
+}
+
+// AddSource adds a source to the scenario, taxed in the given tax account if there is one, and returns it.
+func (b *Builder) AddSource(is *Source, ta ...*TaxAccount) *Source {
+	b.s.Sources = append(b.s.Sources, is)
+	if len(ta) > 0 && ta[0] != nil {
+		b.s.TaxAccounts[is] = ta[0]
+	}
+	return is
+}
+
+// DrawFrom adds entries to the end of the draw sequence.
+// An entry may be a source or a combination of sources such as Seq or Split.
+func (b *Builder) DrawFrom(entries ...*Source) *Builder {
+	b.s.DrawSequence = append(b.s.DrawSequence, entries...)
+	return b
+}
+
+// PayTaxFrom adds sources to the end of the tax payment sequence.
+func (b *Builder) PayTaxFrom(sources ...*Source) *Builder {
+	b.s.TaxPaymentSequence = append(b.s.TaxPaymentSequence, sources...)
+	return b
+}
+
+// EveryYear adds an action performed at the start of every year, before any withdrawals are made.
+func (b *Builder) EveryYear(action func(year int, need int64, s *DrawScenario)) *Builder {
+	b.s.Actions = append(b.s.Actions, action)
+	return b
+}
+
+// InYear adds an action performed at the start of the given year only.
+func (b *Builder) InYear(year int, action func(year int, need int64, s *DrawScenario)) *Builder {
+	return b.EveryYear(func(y int, need int64, s *DrawScenario) {
+		if y == year {
+			action(y, need, s)
+		}
+	})
+}
+
+// LinkToInflation returns a variable with the given year 1 value which rises with inflation each year,
+// such as an allowance to be used with Seq.
+func (b *Builder) LinkToInflation(value int64) *int64 {
+	v := &value
+	b.s.InflationLinkedVariables = append(b.s.InflationLinkedVariables, v)
+	return v
+}
+
+// Calendar ties the years of the scenario to dates and tax years, as WithCalendar does.
+func (b *Builder) Calendar(c Calendar, people ...Person) *Builder {
+	b.s.WithCalendar(c, people...)
+	return b
+}
+
+// Home adds the main residence to the scenario, as WithHome does.
+func (b *Builder) Home(h *Home) *Builder {
+	b.s.WithHome(h)
+	return b
+}
+
+// Care adds long-term care to the scenario, as WithCare does.
+// Unlike care added with WithCare after the scenario is defined, care added within DefineWith is rebuilt by Clone.
+func (b *Builder) Care(c *Care) *Builder {
+	b.s.WithCare(c)
+	return b
+}
+
+// Build returns the scenario, or the Problems found by Validate.
+func (b *Builder) Build() (*DrawScenario, error) {
+	if err := b.s.Validate(); err != nil {
+		return nil, err
+	}
+	return b.s, nil
+}
+
+// MustBuild is like Build but panics if the scenario is invalid.
+// It is intended for scenarios, such as the built-in ones, which are fixed in code.
+func (b *Builder) MustBuild() *DrawScenario {
+	s, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
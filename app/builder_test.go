@@ -0,0 +1,68 @@
+package drawdown
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuilderTaxWithRegistersEachRegimeOnce(t *testing.T) {
+	b := NewBuilder()
+	incomeTax := incomeTaxRegime()
+	first := b.TaxWith("Income Tax 1", incomeTax)
+	second := b.TaxWith("Income Tax 2", incomeTax)
+	b.TaxWith("Capital Gains Tax", NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(HighUpperBound, 18)}))
+	if first == second {
+		t.Error("the same account was returned for two names")
+	}
+	if len(b.s.TaxRegimes) != 2 {
+		t.Fatalf("got %d regimes, want the income tax regime once and the capital gains tax regime", len(b.s.TaxRegimes))
+	}
+	// Both accounts share the registered regime's bands, so they rise with it.
+	if !b.s.TaxRegimes[0].same(first.regime) || !b.s.TaxRegimes[0].same(second.regime) {
+		t.Error("the accounts do not share the registered regime")
+	}
+	// An equal regime with bands of its own is registered separately.
+	b.TaxWith("Income Tax 3", incomeTaxRegime())
+	if len(b.s.TaxRegimes) != 3 {
+		t.Errorf("got %d regimes, want a third for a copy of the income tax regime", len(b.s.TaxRegimes))
+	}
+}
+
+func TestBuilderBuildReturnsProblems(t *testing.T) {
+	b := NewBuilder()
+	pension := b.AddSource(NewPensionAccount("Pension", 1000, &b.Rates().InvestmentGrowthRate))
+	b.DrawFrom(pension)
+	s, err := b.Build()
+	var ps Problems
+	if s != nil || !errors.As(err, &ps) || len(ps) != 1 || ps[0].Source != "Pension" {
+		t.Errorf("got %v, %v, want the problem of the pension without a tax account", s, err)
+	}
+
+	b.s.TaxAccounts[pension] = b.TaxWith("Income Tax", incomeTaxRegime())
+	if s, err := b.Build(); s == nil || err != nil {
+		t.Errorf("got %v, %v once the problem was fixed", s, err)
+	}
+}
+
+func TestBuilderMustBuildPanics(t *testing.T) {
+	defer func() {
+		var ps Problems
+		if err, ok := recover().(error); !ok || !errors.As(err, &ps) {
+			t.Errorf("got %v, want a panic with the Problems", err)
+		}
+	}()
+	NewBuilder().MustBuild()
+	t.Error("MustBuild returned a scenario with an empty draw sequence")
+}
+
+func TestBuilderAddsHomeAndCalendar(t *testing.T) {
+	b := NewBuilder()
+	home := NewHome("Home", 400000, &b.Rates().HousePriceGrowthRate)
+	b.DrawFrom(b.AddSource(NewSavingsAccount("Savings", 1000, &b.Rates().SavingsGrowthRate))).
+		Home(home).
+		Calendar(NewTaxYearCalendar(2025))
+	s := b.MustBuild()
+	if s.Home != home || s.Calendar == nil || s.Calendar.TaxYear(1) != "2025/26" {
+		t.Errorf("got home %v and calendar %v", s.Home, s.Calendar)
+	}
+}
@@ -0,0 +1,79 @@
+package drawdown
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar maps the simulation years onto dates and UK tax years (6 April to 5 April).
+// Year 1 starts on the Start date and each year lasts one calendar year.
+// When Start is 6 April each simulation year is exactly one tax year.
+type Calendar struct {
+	Start time.Time
+}
+
+// NewCalendar creates a calendar whose first year starts on the given date.
+func NewCalendar(start time.Time) Calendar {
+	return Calendar{Start: start}
+}
+
+// NewTaxYearCalendar creates a calendar whose first year is the tax year starting on 6 April of the given year.
+func NewTaxYearCalendar(firstTaxYear int) Calendar {
+	return NewCalendar(time.Date(firstTaxYear, time.April, 6, 0, 0, 0, 0, time.UTC))
+}
+
+// YearStart returns the date on which the given year starts.
+func (c Calendar) YearStart(year int) time.Time {
+	return c.Start.AddDate(year-1, 0, 0)
+}
+
+// YearEnd returns the last day of the given year.
+func (c Calendar) YearEnd(year int) time.Time {
+	return c.YearStart(year+1).AddDate(0, 0, -1)
+}
+
+// YearOf returns the year containing the given date.
+// Dates before the start give a year of zero or less.
+func (c Calendar) YearOf(date time.Time) int {
+	year := date.Year() - c.Start.Year() + 1
+	if date.Before(c.YearStart(year)) {
+		year--
+	}
+	return year
+}
+
+// TaxYear returns the label, such as "2025/26", of the UK tax year in which the given year starts.
+func (c Calendar) TaxYear(year int) string {
+	start := c.YearStart(year)
+	first := start.Year()
+	if start.Before(time.Date(first, time.April, 6, 0, 0, 0, 0, start.Location())) {
+		first--
+	}
+	return fmt.Sprintf("%d/%02d", first, (first+1)%100)
+}
+
+// Age returns the person's age at the start of the given year.
+func (c Calendar) Age(p Person, year int) int {
+	return p.AgeOn(c.YearStart(year))
+}
+
+// YearAtAge returns the year in which the person reaches the given age.
+// If they reach it before the start the result is zero or less.
+func (c Calendar) YearAtAge(p Person, age int) int {
+	return c.YearOf(p.DateOfBirth.AddDate(age, 0, 0))
+}
+
+// Person is someone whose age is used to label the results and to time events such as the start of the state pension.
+type Person struct {
+	Name        string
+	DateOfBirth time.Time
+}
+
+// AgeOn returns the person's age in whole years on the given date.
+func (p Person) AgeOn(date time.Time) int {
+	age := date.Year() - p.DateOfBirth.Year()
+	if date.Before(p.DateOfBirth.AddDate(age, 0, 0)) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,81 @@
+package drawdown
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCalendarTaxYear(t *testing.T) {
+	tests := []struct {
+		c    Calendar
+		year int
+		want string
+	}{
+		{NewTaxYearCalendar(2025), 1, "2025/26"},
+		{NewTaxYearCalendar(2025), 2, "2026/27"},
+		{NewTaxYearCalendar(2025), 75, "2099/00"},
+		{NewCalendar(date(2025, time.January, 1)), 1, "2024/25"},
+		{NewCalendar(date(2025, time.April, 5)), 1, "2024/25"},
+		{NewCalendar(date(2025, time.April, 6)), 1, "2025/26"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.TaxYear(tt.year); got != tt.want {
+			t.Errorf("%v: TaxYear(%d) = %q, want %q", tt.c.Start.Format(time.DateOnly), tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestCalendarAge(t *testing.T) {
+	c := NewTaxYearCalendar(2025)
+	tests := []struct {
+		born time.Time
+		year int
+		want int
+	}{
+		{date(1957, time.March, 1), 1, 68},
+		{date(1961, time.September, 1), 1, 63},
+		{date(1961, time.September, 1), 2, 64},
+		{date(1960, time.April, 6), 1, 65}, // A birthday on the first day of the year counts.
+		{date(1960, time.April, 7), 1, 64},
+	}
+	for _, tt := range tests {
+		p := Person{DateOfBirth: tt.born}
+		if got := c.Age(p, tt.year); got != tt.want {
+			t.Errorf("born %v: Age in year %d = %d, want %d", tt.born.Format(time.DateOnly), tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestPersonAgeOnALeapDayBirthday(t *testing.T) {
+	p := Person{DateOfBirth: date(1960, time.February, 29)}
+	if got := p.AgeOn(date(2025, time.February, 28)); got != 64 {
+		t.Errorf("age on 28 February = %d, want 64", got)
+	}
+	if got := p.AgeOn(date(2025, time.March, 1)); got != 65 {
+		t.Errorf("age on 1 March = %d, want 65", got)
+	}
+}
+
+func TestCalendarYearAtAge(t *testing.T) {
+	c := NewTaxYearCalendar(2025)
+	tests := []struct {
+		born time.Time
+		age  int
+		want int
+	}{
+		{date(1961, time.September, 1), 67, 4}, // 1 September 2028, in 2028/29.
+		{date(1962, time.April, 5), 67, 4},     // 5 April 2029, the last day of 2028/29.
+		{date(1962, time.April, 6), 67, 5},     // 6 April 2029, the first day of 2029/30.
+		{date(1957, time.March, 1), 67, -1},    // 1 March 2024, before the start.
+	}
+	for _, tt := range tests {
+		p := Person{DateOfBirth: tt.born}
+		if got := c.YearAtAge(p, tt.age); got != tt.want {
+			t.Errorf("born %v: YearAtAge(%d) = %d, want %d", tt.born.Format(time.DateOnly), tt.age, got, tt.want)
+		}
+	}
+}
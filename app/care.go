@@ -0,0 +1,102 @@
+package drawdown
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Care describes a long-term care event which adds care fees to the need.
+// Care starts either in a given year or, when StartYear is zero, at random
+// with a probability for each year of age drawn using Rand, which by default is seeded with the scenario's Seed.
+// Fees are in year 1 prices and inflate at the scenario's care cost inflation rate.
+type Care struct {
+	Year1AnnualFees  int64
+	StartYear        int                   // The year in which care starts, or 0 to start at random.
+	Years            int                   // The number of years for which care is needed, or 0 for the rest of the run.
+	Year1Age         int                   // The age of the person in year 1.
+	ProbabilityByAge func(age int) float64 // The probability of needing care in a year at a given age.
+	Rand             *rand.Rand            // nil, else the random source used when StartYear is zero.
+	MeansTest        *MeansTest            // nil if the local authority does not contribute.
+	startedYear      int                   // The year in which care started, or 0 if not yet started.
+}
+
+// MeansTest describes a local authority's means test for care funding.
+// Capital above the upper limit pays full fees.
+// Capital between the limits is assumed to provide a tariff income of 1 for every TariffStep per week.
+// Capital below the lower limit is disregarded.
+// Below the upper limit, income such as the state pension is also assessed,
+// less a personal expenses allowance which the person keeps.
+// The limits are int64 so that they can be listed as inflation linked variables.
+type MeansTest struct {
+	UpperCapitalLimit         int64
+	LowerCapitalLimit         int64
+	TariffStep                int64
+	PersonalExpensesAllowance int64 // The annual income kept for personal expenses, which is not assessed.
+	IncludeHome               bool  // The value of the home counts as capital (for example, once nobody else lives there).
+}
+
+// NewEnglishMeansTest returns the English local authority means test.
+func NewEnglishMeansTest(includeHome bool) *MeansTest {
+	return &MeansTest{
+		UpperCapitalLimit:         23250,
+		LowerCapitalLimit:         14250,
+		TariffStep:                250,
+		PersonalExpensesAllowance: 1594, // 30.65 a week.
+		IncludeHome:               includeHome,
+	}
+}
+
+// InCare returns true if care is needed in the given year.
+func (c *Care) InCare(year int) bool {
+	if c.startedYear == 0 {
+		return false
+	}
+	return c.Years == 0 || year < c.startedYear+c.Years
+}
+
+// start decides whether care starts in the given year.
+func (c *Care) start(year int) {
+	if c.startedYear != 0 {
+		return
+	}
+	if c.StartYear != 0 {
+		if year >= c.StartYear {
+			c.startedYear = c.StartYear
+		}
+		return
+	}
+	if c.ProbabilityByAge == nil || c.Rand == nil {
+		return
+	}
+	if c.Rand.Float64() < c.ProbabilityByAge(c.Year1Age+year-1) {
+		c.startedYear = year
+	}
+}
+
+// FeesDue returns the care fees to be added to the need in the given year,
+// after any local authority contribution based on the scenario's capital and income.
+// Sources of income, such as the state pension, count as income rather than capital, and earnings are disregarded.
+func (c *Care) FeesDue(year int, s *DrawScenario) int64 {
+	c.start(year)
+	if !c.InCare(year) {
+		return 0
+	}
+	fees := int64(float64(c.Year1AnnualFees) * math.Pow(1+s.Rates.CareCostInflationRate/100, float64(year-1)))
+	if c.MeansTest == nil {
+		return fees
+	}
+	return min(fees, c.MeansTest.Contribution(s.capital(c.MeansTest.IncludeHome), s.income()))
+}
+
+// Contribution returns the annual amount a person with the given capital and annual income must contribute towards their care.
+func (mt *MeansTest) Contribution(capital int64, income int64) int64 {
+	if capital > mt.UpperCapitalLimit {
+		return math.MaxInt64
+	}
+	contribution := max(0, income-mt.PersonalExpensesAllowance)
+	if capital <= mt.LowerCapitalLimit || mt.TariffStep <= 0 {
+		return contribution
+	}
+	steps := (capital - mt.LowerCapitalLimit + mt.TariffStep - 1) / mt.TariffStep
+	return contribution + steps*52
+}
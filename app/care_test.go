@@ -0,0 +1,139 @@
+package drawdown
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeansTestContribution(t *testing.T) {
+	english := NewEnglishMeansTest(false)
+	noTariff := *english
+	noTariff.TariffStep = 0
+	tests := []struct {
+		name    string
+		mt      *MeansTest
+		capital int64
+		income  int64
+		want    int64
+	}{
+		{"above the upper limit", english, 23251, 10000, math.MaxInt64},
+		// Income of 10,000 less the allowance of 1,594, plus 36 steps of 250 a week above the lower limit.
+		{"at the upper limit", english, 23250, 10000, 8406 + 36*52},
+		{"at the lower limit", english, 14250, 10000, 8406},
+		{"part of a step above the lower limit", english, 14251, 10000, 8406 + 52},
+		{"a whole step above the lower limit", english, 14500, 10000, 8406 + 52},
+		{"just over a step above the lower limit", english, 14501, 10000, 8406 + 2*52},
+		{"income within the allowance", english, 0, 1000, 0},
+		{"no tariff step", &noTariff, 20000, 10000, 8406},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mt.Contribution(tt.capital, tt.income); got != tt.want {
+				t.Errorf("Contribution(%d, %d) = %d, want %d", tt.capital, tt.income, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCareFeesDue(t *testing.T) {
+	savings := NewSavingsAccount("Savings", 20000, new(float64))
+	s := &DrawScenario{Sources: []*Source{savings}, Rates: DrawRates{CareCostInflationRate: 10}}
+	c := &Care{Year1AnnualFees: 50000, StartYear: 2, Years: 2}
+	for year, want := range []int64{0, 55000, 60500, 0} {
+		if got := c.FeesDue(year+1, s); got != want {
+			t.Errorf("year %d: fees %d, want %d", year+1, got, want)
+		}
+	}
+
+	// With 20,000 of capital and no income, 23 steps of 250 a week above the lower limit are contributed.
+	c = &Care{Year1AnnualFees: 50000, StartYear: 1, MeansTest: NewEnglishMeansTest(false)}
+	if got := c.FeesDue(1, s); got != 23*52 {
+		t.Errorf("means tested fees %d, want %d", got, 23*52)
+	}
+}
+
+// careStartYear returns the first year in which care fees were added to the need, or 0 if they never were.
+func careStartYear(t *testing.T, result DrawResult) int {
+	t.Helper()
+	for _, e := range result.Ledger {
+		if e.Kind == NeedEntry && e.Note == "care fees" {
+			return e.Year
+		}
+	}
+	return 0
+}
+
+// defineRandomCareScenario defines a scenario with care which may start at random from age 70, when its year 1 age is 65.
+func defineRandomCareScenario(t *testing.T, probability float64) *DrawScenario {
+	t.Helper()
+	s, err := DefineWith(func(b *Builder) {
+		savings := b.AddSource(NewSavingsAccount("Savings", 2000000, &b.Rates().SavingsGrowthRate))
+		b.DrawFrom(savings).Care(&Care{
+			Year1AnnualFees: 50000,
+			Year1Age:        65,
+			ProbabilityByAge: func(age int) float64 {
+				if age < 70 {
+					return 0
+				}
+				return probability
+			},
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRandomCareStartIsSeeded(t *testing.T) {
+	s := defineRandomCareScenario(t, 0.2)
+	starts := map[int]bool{}
+	for seed := int64(1); seed <= 20; seed++ {
+		first, err := s.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, _ := s.Clone()
+		want, err := first.WithSeed(seed).Iterate(30, 20000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := second.WithSeed(seed).Iterate(30, 20000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := careStartYear(t, want)
+		if careStartYear(t, got) != start {
+			t.Errorf("seed %d: care started in years %d and %d", seed, start, careStartYear(t, got))
+		}
+		if start != 0 && start < 6 {
+			t.Errorf("seed %d: care started in year %d, before age 70", seed, start)
+		}
+		starts[start] = true
+	}
+	if len(starts) < 2 {
+		t.Errorf("care started in the same year, %v, for every seed", starts)
+	}
+}
+
+func TestRandomCareCertainties(t *testing.T) {
+	tests := []struct {
+		name        string
+		probability float64
+		want        int
+	}{
+		{"never", 0, 0},
+		{"certain from age 70", 1, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := defineRandomCareScenario(t, tt.probability).Iterate(30, 20000)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := careStartYear(t, result); got != tt.want {
+				t.Errorf("care started in year %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package drawdown
+
+import "errors"
+
+// ErrNotDefined is returned by Clone for a scenario which was not made by Define or DefineWith,
+// or whose components, home or care have been replaced since.
+var ErrNotDefined = errors.New("the scenario was not made by Define or DefineWith, or has been changed since, so it cannot be cloned")
+
+// Define returns the scenario built by define and remembers define so that the scenario can be cloned.
+// Define must build a new scenario each time it is called, sharing no sources, tax accounts,
+// regimes or variables with any scenario it built before.
+func Define(define func() *DrawScenario) *DrawScenario {
+	s := define()
+	s.define = define
+	return s
+}
+
+// Clone returns a new, unused copy of the scenario, with the same rates, options, seed and calendar,
+// which can be iterated independently of, and concurrently with, the scenario and its other clones.
+// Since the sources and actions of a scenario are closures which cannot be copied,
+// the clone is built afresh by the function given to Define or DefineWith.
+// WithComponents, WithHome and WithCare replace parts of the scenario which cannot be rebuilt,
+// so once they have been called after it was defined, Clone returns ErrNotDefined rather than a different scenario.
+// A scenario is used up by Iterate, so a clone should be taken for each run.
+func (s *DrawScenario) Clone() (*DrawScenario, error) {
+	if s.define == nil {
+		return nil, ErrNotDefined
+	}
+	c := s.define()
+	c.define = s.define
+	c.Rates = s.Rates
+	c.ContinueAfterShortfall = s.ContinueAfterShortfall
+	c.Seed = s.Seed
+	c.Calendar = s.Calendar
+	c.People = s.People
+	return c, nil
+}
@@ -0,0 +1,151 @@
+package drawdown
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// defineTestScenario defines a small scenario which uses capped draws, an action and tax,
+// so that any state shared between clones would change their results.
+func defineTestScenario(t *testing.T) *DrawScenario {
+	t.Helper()
+	s, err := DefineWith(func(b *Builder) {
+		r := b.Rates()
+		incomeTax := b.TaxWith("Income Tax", NewTaxRegime([]RateBound{
+			NewRateBound(12570, 0),
+			NewRateBound(50270, 20),
+			NewRateBound(HighUpperBound, 40),
+		}))
+		cgtRegime := NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(HighUpperBound, 18)})
+		cgt := b.TaxWith("Capital Gains Tax", cgtRegime)
+		statePension := b.AddSource(NewStatePension("State Pension", 11000, 2.5, 3), incomeTax)
+		pension := b.AddSource(NewPensionAccount("Pension", 300000, &r.InvestmentGrowthRate), incomeTax)
+		savings := b.AddSource(NewSavingsAccount("Savings", 40000, &r.SavingsGrowthRate))
+		isa := b.AddSource(NewInvestmentAccount("ISA", 20000, &r.InvestmentGrowthRate))
+		gia := b.AddSource(NewInvestmentAccount("GIA", 60000, &r.InvestmentGrowthRate).Taxable(), cgt)
+		allowance := b.LinkToInflation(cgtRegime.TaxFreeAllowance())
+		isaAllowance := b.LinkToInflation(5000)
+		b.DrawFrom(statePension, Seq(allowance, gia), savings, isa, pension, gia).
+			PayTaxFrom(savings, isa, pension, gia).
+			EveryYear(func(year int, need int64, s *DrawScenario) {
+				Transfer(isaAllowance, isa, savings)
+			})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.WithRates(DrawRates{InvestmentGrowthRate: 4, SavingsGrowthRate: 3, AnnualInflationRate: 2.5, PlatformChargeRate: 0.25, TaxBandAnnualPctIncrease: 1})
+}
+
+func TestCloneRunsConcurrentlyWithIdenticalResults(t *testing.T) {
+	s := defineTestScenario(t)
+	want, err := s.Iterate(30, 30000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const runs = 8
+	results := make([]DrawResult, runs)
+	errs := make([]error, runs)
+	var wg sync.WaitGroup
+	for i := range runs {
+		c, err := s.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				results[i], errs[i] = c.Iterate(30, 30000)
+			} else {
+				results[i], errs[i] = c.IterateMonthly(30, 30000)
+			}
+		}()
+	}
+	wg.Wait()
+
+	monthly, err := s.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMonthly, err := monthly.IterateMonthly(30, 30000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range runs {
+		if errs[i] != nil {
+			t.Fatalf("run %d: %v", i, errs[i])
+		}
+		w := want
+		if i%2 == 1 {
+			w = wantMonthly
+		}
+		if !reflect.DeepEqual(results[i], w) {
+			t.Errorf("run %d differs from a run of the original scenario", i)
+		}
+	}
+}
+
+func TestIterateRefusesAUsedScenario(t *testing.T) {
+	s := defineTestScenario(t)
+	if _, err := s.Iterate(5, 30000); err != nil {
+		t.Fatal(err)
+	}
+	var is *InvalidScenario
+	if _, err := s.Iterate(5, 30000); !errors.As(err, &is) {
+		t.Errorf("second Iterate: got %v, want an *InvalidScenario", err)
+	}
+	c, err := s.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Iterate(5, 30000); err != nil {
+		t.Errorf("Iterate of a clone of a used scenario: %v", err)
+	}
+}
+
+func TestCloneKeepsRatesOptionsSeedAndCalendar(t *testing.T) {
+	s := defineTestScenario(t)
+	s.WithContinueAfterShortfall(true).WithSeed(42).WithCalendar(NewTaxYearCalendar(2030))
+	c, err := s.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Rates != s.Rates || !c.ContinueAfterShortfall || c.Seed != 42 || c.Calendar == nil || c.Calendar.TaxYear(1) != "2030/31" {
+		t.Errorf("clone lost the rates, options, seed or calendar: %+v", c)
+	}
+}
+
+func TestCloneRefusesScenariosItCannotRebuild(t *testing.T) {
+	tests := []struct {
+		name string
+		s    func(t *testing.T) *DrawScenario
+	}{
+		{"built without DefineWith", func(t *testing.T) *DrawScenario {
+			b := NewBuilder()
+			b.DrawFrom(b.AddSource(NewSavingsAccount("Savings", 1000, &b.Rates().SavingsGrowthRate)))
+			s, err := b.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		}},
+		{"care added after defining", func(t *testing.T) *DrawScenario {
+			return defineTestScenario(t).WithCare(&Care{Year1AnnualFees: 50000, StartYear: 10})
+		}},
+		{"home added after defining", func(t *testing.T) *DrawScenario {
+			rate := 2.0
+			return defineTestScenario(t).WithHome(NewHome("Home", 400000, &rate))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.s(t).Clone(); !errors.Is(err, ErrNotDefined) {
+				t.Errorf("got %v, want ErrNotDefined", err)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package drawdown
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"math/rand"
+	"strings"
 )
 
 type DrawRates struct {
@@ -11,6 +14,8 @@ type DrawRates struct {
 	AnnualInflationRate      float64
 	PlatformChargeRate       float64
 	TaxBandAnnualPctIncrease float64
+	HousePriceGrowthRate     float64
+	CareCostInflationRate    float64
 }
 
 type DrawScenario struct {
@@ -21,7 +26,15 @@ type DrawScenario struct {
 	TaxRegimes               []*TaxRegime
 	Actions                  []func(year int, need int64, s *DrawScenario)
 	InflationLinkedVariables []*int64
+	Home                     *Home     // nil if the scenario does not include the main residence.
+	Care                     *Care     // nil if the scenario does not include long-term care.
+	Calendar                 *Calendar // nil if the years are not tied to dates.
+	People                   []Person
+	ContinueAfterShortfall   bool  // Keep iterating after a year in which the need cannot be met.
+	Seed                     int64 // The seed of random events, such as the start of care, so that a run can be repeated.
 	Rates                    DrawRates
+	define                   func() *DrawScenario // nil, else the function which defined the scenario, used by Clone.
+	iterated                 bool                 // The scenario has been iterated, using up its balances.
 }
 
 func (s *DrawScenario) WithComponents(
@@ -40,6 +53,91 @@ func (s *DrawScenario) WithComponents(
 	s.TaxRegimes = taxRegimes
 	s.Actions = actions
 	s.InflationLinkedVariables = inflationLinkedVariables
+	s.define = nil // The new components cannot be rebuilt by Clone.
+	return s
+}
+
+func (s *DrawScenario) WithHome(h *Home) *DrawScenario {
+	s.Home = h
+	s.define = nil // The home cannot be rebuilt by Clone.
+	return s
+}
+
+// WithCalendar ties the years of the scenario to dates and tax years
+// and labels the results with the ages of the given people.
+func (s *DrawScenario) WithCalendar(c Calendar, people ...Person) *DrawScenario {
+	s.Calendar = &c
+	s.People = people
+	return s
+}
+
+// labels returns the tax year of the given year and the ages of the scenario's people at its start.
+func (s *DrawScenario) labels(year int) (string, []int) {
+	if s.Calendar == nil {
+		return "", nil
+	}
+	ages := make([]int, len(s.People))
+	for i, p := range s.People {
+		ages[i] = s.Calendar.Age(p, year)
+	}
+	return s.Calendar.TaxYear(year), ages
+}
+
+func (s *DrawScenario) WithCare(c *Care) *DrawScenario {
+	s.Care = c
+	s.define = nil // The care, which holds its own state, cannot be rebuilt by Clone.
+	return s
+}
+
+// capital returns the balance of the liquid sources, other than sources of income, the value of any rental property
+// less its mortgage and, optionally, the value of the home.
+func (s *DrawScenario) capital(includeHome bool) int64 {
+	capital := int64(0)
+	for _, source := range s.Sources {
+		if source.Kind() == Liquid && !source.paysIncome {
+			capital += source.Balance()
+		}
+		capital += max(0, source.propertyValue-source.propertyDebt)
+	}
+	if includeHome && s.Home != nil {
+		capital += s.Home.Balance()
+	}
+	return capital
+}
+
+// income returns the income for the year from the sources of income assessed by a care means test.
+func (s *DrawScenario) income() int64 {
+	income := int64(0)
+	for _, source := range s.Sources {
+		income += source.income
+	}
+	return income
+}
+
+// periodShare returns the part of an annual amount which falls in the given period of a year divided into periods equal periods.
+// The shares of all the periods sum to the annual amount.
+func periodShare(annual int64, period int, periods int) int64 {
+	return annual*int64(period)/int64(periods) - annual*int64(period-1)/int64(periods)
+}
+
+// allSources returns the scenario's sources followed by the main residence, if any.
+func (s *DrawScenario) allSources() []*Source {
+	if s.Home == nil {
+		return s.Sources
+	}
+	return append(s.Sources[:len(s.Sources):len(s.Sources)], s.Home.Source)
+}
+
+// WithContinueAfterShortfall sets whether iteration continues after a year in which the need cannot be met.
+// When it does, the unmet need of each year is recorded as a Shortfall and is not carried into the next year.
+func (s *DrawScenario) WithContinueAfterShortfall(c bool) *DrawScenario {
+	s.ContinueAfterShortfall = c
+	return s
+}
+
+// WithSeed sets the seed of the scenario's random events.
+func (s *DrawScenario) WithSeed(seed int64) *DrawScenario {
+	s.Seed = seed
 	return s
 }
 
@@ -49,110 +147,302 @@ func (s *DrawScenario) WithRates(r DrawRates) *DrawScenario {
 }
 
 // A Transaction represents the situation, for a given source, at the end of the year.
+// Its Flows reconcile the opening balance with the closing Balance.
 // Withdrawals from a source may cause tax to be raised.
 // Tax raised may be paid by the same source, or a different source.
 // The Amount of a transaction includes any amount withdrawn to pay tax.
 type Transaction struct {
-	Year      int
-	Source    string
-	Amount    int64 // The amount withdrawn from this source (including tax paid).
-	Tax       int64 // (the amount of) Tax paid from this source.
-	TaxRaised int64 // (the amount of) Tax raised as a result of withdrawing from this source.
-	Balance   int64 // Remaining value in the source.
+	Year       int
+	TaxYear    string // The UK tax year, such as "2025/26", if the scenario has a calendar.
+	Ages       []int  // The ages of the scenario's people at the start of the year.
+	Source     string
+	Kind       SourceKind
+	Amount     int64   // The amount withdrawn from this source (including tax paid).
+	Tax        int64   // (the amount of) Tax paid from this source.
+	TaxRaised  int64   // (the amount of) Tax raised as a result of withdrawing from this source.
+	Balance    int64   // Remaining value in the source.
+	PriceIndex float64 // The price level relative to year 1, by which amounts are divided to give them in today's money.
+	Flows
+	PropertyValue int64 // The value of a property held by the source, such as a rental property, which is not part of its balance.
+	PropertyDebt  int64 // The mortgage secured on the property.
+}
+
+// Real returns the transaction with its amounts deflated to today's (year 1) money.
+func (t Transaction) Real() Transaction {
+	if t.PriceIndex == 0 || t.PriceIndex == 1 {
+		return t
+	}
+	r := t
+	deflate := func(a int64) int64 { return int64(float64(a) / t.PriceIndex) }
+	r.Amount = deflate(t.Amount)
+	r.Tax = deflate(t.Tax)
+	r.TaxRaised = deflate(t.TaxRaised)
+	r.Balance = deflate(t.Balance)
+	r.OpeningBalance = deflate(t.OpeningBalance)
+	r.Growth = deflate(t.Growth)
+	r.Fees = deflate(t.Fees)
+	r.Deposits = deflate(t.Deposits)
+	r.Withdrawals = deflate(t.Withdrawals)
+	r.PropertyValue = deflate(t.PropertyValue)
+	r.PropertyDebt = deflate(t.PropertyDebt)
+	r.PriceIndex = 1
+	return r
 }
 
 type DrawHistory []Transaction
 
-// Iterate returns a transaction for each combination of Source and increasing Year.
-func (s *DrawScenario) Iterate(years int, year1AnnualIncome int) DrawHistory {
+// Real returns the history with every amount deflated to today's (year 1) money.
+func (h DrawHistory) Real() DrawHistory {
+	r := make(DrawHistory, len(h))
+	for i, t := range h {
+		r[i] = t.Real()
+	}
+	return r
+}
+
+// MonthsPerYear is the number of periods in each year simulated by IterateMonthly.
+const MonthsPerYear = 12
+
+// Iterate returns a transaction for each combination of Source and increasing Year,
+// together with any shortfall or unpaid tax events, and the tax raised in the last year run, which falls due after it.
+// Unless the scenario continues after shortfall, iteration stops at the end of the first year in which the need cannot be met.
+// If Validate finds mistakes, nothing is run and the error is the Problems it found.
+// If the scenario cannot be simulated part way through, the error is an *InvalidScenario
+// and the result holds the years completed before the problem arose.
+// Either way errors.As with an *InvalidScenario finds the (first) problem.
+func (s *DrawScenario) Iterate(years int, year1AnnualIncome int) (DrawResult, error) {
+	return s.iterate(context.Background(), years, year1AnnualIncome, 1)
+}
+
+// IterateContext is like Iterate but stops at the start of a year once ctx is done,
+// returning the years completed and ctx.Err().
+func (s *DrawScenario) IterateContext(ctx context.Context, years int, year1AnnualIncome int) (DrawResult, error) {
+	return s.iterate(ctx, years, year1AnnualIncome, 1)
+}
+
+// IterateMonthly is like Iterate but simulates each year month by month.
+// Each simulation year is a UK tax year starting on 6 April, so month 1 runs from 6 April to 5 May.
+// The year's need is spread evenly across the months, savings and investments grow monthly,
+// income such as the state pension is paid monthly, and platform charges are levied monthly.
+// Tax is reconciled at the end of each tax year on the total withdrawn in that year.
+// Limits such as those of Seq and Upto apply to the year as a whole, not to each month.
+// Growth is credited month by month from the first month, whereas Iterate credits a year's growth
+// at the beginning of the next year, so by the end of each year sources have had a year more growth than with Iterate.
+// The results are aggregated to one transaction per source per year, as for Iterate.
+func (s *DrawScenario) IterateMonthly(years int, year1AnnualIncome int) (DrawResult, error) {
+	return s.iterate(context.Background(), years, year1AnnualIncome, MonthsPerYear)
+}
+
+// IterateMonthlyContext is like IterateMonthly but stops at the start of a year once ctx is done,
+// returning the years completed and ctx.Err().
+func (s *DrawScenario) IterateMonthlyContext(ctx context.Context, years int, year1AnnualIncome int) (DrawResult, error) {
+	return s.iterate(ctx, years, year1AnnualIncome, MonthsPerYear)
+}
+
+// iterate simulates the given number of years, each divided into periods equal periods, until ctx is done.
+func (s *DrawScenario) iterate(ctx context.Context, years int, year1AnnualIncome int, periods int) (result DrawResult, err error) {
+	if s.iterated {
+		return DrawResult{}, &InvalidScenario{Reason: "already iterated, which used up its balances; Clone it to run it again"}
+	}
+	if err := s.Validate(); err != nil {
+		return DrawResult{}, err
+	}
+	s.iterated = true
+	if s.Care != nil && s.Care.Rand == nil {
+		s.Care.Rand = rand.New(rand.NewSource(s.Seed))
+	}
 
 	transactions := []Transaction{}
+	events := []Event{}
+	ledger := Ledger{}
+	sources := s.allSources()
+	for _, source := range sources {
+		source.ledger = &ledger
+	}
+
+	year := 0
+	// Sources and tax regimes panic with an *InvalidScenario if they are asked to do something impossible.
+	defer func() {
+		if r := recover(); r != nil {
+			is, ok := r.(*InvalidScenario)
+			if !ok {
+				panic(r)
+			}
+			if is.Year == 0 {
+				is.Year = year
+			}
+			result, err = DrawResult{History: transactions, Events: events, Ledger: ledger}, is
+		}
+	}()
 
 	var unpaidTax int64 = 0
-	for year := 1; year <= years; year++ {
-		var need int64 = int64(float64(year1AnnualIncome) * math.Pow(1+s.Rates.AnnualInflationRate/100, float64(year-1)))
-		need += unpaidTax
-		unpaidTax = 0
-		//fmt.Println("year", year, "need", need)
+	priceIndex := 1.0 // The price level at the start of the year relative to year 1.
+	for year = 1; year <= years; year++ {
+		if err := ctx.Err(); err != nil {
+			return DrawResult{History: transactions, Events: events, Ledger: ledger}, err
+		}
+		var annualNeed int64 = int64(float64(year1AnnualIncome) * math.Pow(1+s.Rates.AnnualInflationRate/100, float64(year-1)))
+		ledger.add(LedgerEntry{Year: year, Kind: NeedEntry, Amount: annualNeed, Note: fmt.Sprintf("income of %d inflated at %.2f%% a year", year1AnnualIncome, s.Rates.AnnualInflationRate)})
 
 		// Start of year.
-		for _, source := range s.Sources {
-			source.StartYear(year)
-		}
+		// Tax accounts are reset first so that sources may raise tax as they open the year.
 		for _, ta := range s.TaxAccounts {
 			ta.Reset(year)
 		}
+		for _, source := range sources {
+			source.openYear()
+		}
+		for _, source := range append(s.DrawSequence[:len(s.DrawSequence):len(s.DrawSequence)], s.TaxPaymentSequence...) {
+			source.startDrawing()
+		}
+		for _, source := range sources {
+			source.StartPeriod(year, 1, periods)
+		}
+
+		withdrawn := make(map[*Source]int64)    // Amount withdrawn from each source this year.
+		drawn := []*Source{}                    // The sources withdrawn from this year, in the order first drawn.
+		taxWithdrawn := make(map[*Source]int64) // Amount withdrawn from each source this year to pay tax.
+		draw := func(sa SourceAmount, note string) {
+			if _, ok := withdrawn[sa.Source]; !ok {
+				drawn = append(drawn, sa.Source)
+			}
+			withdrawn[sa.Source] += sa.Amount
+			if sa.Amount > 0 {
+				ledger.add(LedgerEntry{Year: year, Source: sa.Source.Name, Kind: WithdrawalEntry, Amount: sa.Amount, Note: note})
+			}
+		}
+
+		// Pay last year's tax from the tax payment sequence. A withdrawal from a taxable source
+		// is assessed with the rest of this year's withdrawals. Tax which cannot be paid is added to the need.
+		for _, source := range s.TaxPaymentSequence {
+			for _, sa := range source.Withdraw(unpaidTax) { // Source might split withdrawal between multiple sub-sources.
+				unpaidTax -= sa.Amount
+				taxWithdrawn[sa.Source] += sa.Amount
+				draw(sa, "last year's tax paid by "+source.Name)
+			}
+		}
+		if unpaidTax > 0 {
+			events = append(events, UnpaidTax{Year: year, Amount: unpaidTax})
+			ledger.add(LedgerEntry{Year: year, Kind: NeedEntry, Amount: unpaidTax, Note: "tax raised last year which the tax payment sequence could not pay"})
+			annualNeed += unpaidTax
+			unpaidTax = 0
+		}
+
 		// Actions
 		for _, a := range s.Actions {
-			a(year, need, s)
+			a(year, annualNeed, s)
 		}
 
-		// Platform charges
-		balance := int64(0)
-		for _, source := range s.Sources {
-			balance += source.PlatformChargeBalance()
+		// Scheduled repayments of liabilities.
+		for _, source := range sources {
+			if repayment := source.RepaymentDue(); repayment > 0 {
+				annualNeed += repayment
+				ledger.add(LedgerEntry{Year: year, Source: source.Name, Kind: NeedEntry, Amount: repayment, Note: "scheduled repayment"})
+			}
 		}
-		platformCharges := int64(float64(balance) * s.Rates.PlatformChargeRate / 100)
-		need += platformCharges
-		//fmt.Println("year", year, "balance", balance, "charges", platformCharges)
 
-		// Withdrawals
-		withdrawn := make(map[*Source]int64) // Amount withdrawn from each source this year.
-		for _, source := range s.DrawSequence {
-			iss := source.Withdraw(need) // Source might split withdrawal between multiple sub-sources.
-			for _, is := range iss {
-				need -= is.Amount
-				need = max(0, need) // Some sources, such as the State Pension, may return more than needed.
-				withdrawn[is.Source] += is.Amount
-				//fmt.Println("year", year, "source", is.Source.Name, "amount", is.Amount, "balance", is.Source.balance)
+		// Care fees
+		if s.Care != nil {
+			if fees := s.Care.FeesDue(year, s); fees > 0 {
+				annualNeed += fees
+				ledger.add(LedgerEntry{Year: year, Kind: NeedEntry, Amount: fees, Note: "care fees"})
 			}
 		}
+
+		need := int64(0) // Need not yet met this year.
+		for period := 1; period <= periods; period++ {
+			if period > 1 {
+				for _, source := range sources {
+					source.StartPeriod(year, period, periods)
+				}
+			}
+			need += periodShare(annualNeed, period, periods)
+
+			// Platform charges are deducted from each charged source.
+			for _, source := range s.Sources {
+				balance := source.Balance()
+				charge := source.chargeFee(source.fee(s.Rates.PlatformChargeRate, periods))
+				if charge > 0 {
+					note := fmt.Sprintf("%.2f%% a year on a balance of %d", s.Rates.PlatformChargeRate, balance)
+					if source.fees != nil {
+						note = fmt.Sprintf("fee schedule on a balance of %d", balance)
+					}
+					ledger.add(LedgerEntry{Year: year, Source: source.Name, Kind: PlatformChargeEntry, Amount: charge, Note: note})
+				}
+			}
+
+			// Withdrawals
+			for _, source := range s.DrawSequence {
+				iss := source.Withdraw(max(0, need)) // Source might split withdrawal between multiple sub-sources.
+				for _, is := range iss {
+					// Some sources, such as the State Pension, may return more than needed.
+					// The surplus meets the need of later periods of the year.
+					need -= is.Amount
+					draw(is, "drawn by "+source.Name)
+				}
+			}
+		}
+		need = max(0, need) // A surplus is not carried into the next year.
 		// Tax
 		taxRaised := make(map[*Source]int64) // Tax amount raised from each source this year.
 		taxToPay := int64(0)
-		for is, w := range withdrawn {
-			ta, taxable := s.TaxAccounts[is]
-			if taxable {
-				tax := ta.TaxOn(w)
+		// Money moved out of a source by an action, such as Transfer or PayOff, does not meet the need,
+		// but a taxable source is assessed on it with the rest of the year's withdrawals.
+		moved := make(map[*Source]int64)
+		for _, source := range sources {
+			if m := source.takeMoved(); m > 0 {
+				if _, ok := withdrawn[source]; !ok {
+					drawn = append(drawn, source)
+				}
+				moved[source] = m
+			}
+		}
+		for _, is := range drawn {
+			w := withdrawn[is] + moved[is]
+			ta := s.TaxAccounts[is] // nil if the source is not taxable.
+			note := taxNote(ta, w)
+			regimeTax := int64(0)
+			if ta != nil {
+				regimeTax = ta.TaxDue(w)
+			}
+			tax := is.TaxOn(ta, w) // Some sources, such as earnings, raise more than income tax.
+			if tax != regimeTax {
+				note += fmt.Sprintf("; %+d from charges or credits specific to the source, such as National Insurance", tax-regimeTax)
+			}
+			if tax > 0 {
 				taxToPay += tax
 				taxRaised[is] += tax
-				//fmt.Println(is.Name, "withdrawn", w, "taxable", taxable, "ta", ta.Name, "tax", tax)
+				ledger.add(LedgerEntry{Year: year, Source: is.Name, Kind: TaxEntry, Amount: tax, Note: note})
 			}
 		}
-		// Pay tax
-		taxWithdrawn := make(map[*Source]int64) // Amount withdrawn from each source this year to pay tax.
-		payTaxNextYear := true
-		if payTaxNextYear {
-			unpaidTax = taxToPay
-		} else {
-
-			for _, source := range s.TaxPaymentSequence {
-				sas := source.Withdraw(taxToPay) // Source might split withdrawal between multiple sub-sources.
-				for _, sa := range sas {
-					taxToPay -= sa.Amount
-					withdrawn[sa.Source] += sa.Amount
-					taxWithdrawn[sa.Source] += sa.Amount
-					// If paying tax from a taxable source, calculate the additional tax raised on that withdrawal.
-					ta, taxable := s.TaxAccounts[sa.Source]
-					if taxable {
-						unpaidTax += ta.TaxDue(sa.Amount)
-					}
-				}
-			}
-			if taxToPay > 0 {
-				fmt.Println("Some tax unpaid:", taxToPay)
+		for _, source := range sources {
+			if tax := source.TakeRaisedTax(); tax > 0 {
+				taxToPay += tax
+				taxRaised[source] += tax
+				ledger.add(LedgerEntry{Year: year, Source: source.Name, Kind: TaxEntry, Amount: tax, Note: "raised on a disposal"})
 			}
 		}
+		// The tax raised is paid next year.
+		unpaidTax = taxToPay
 
 		// End of year.
-		for _, source := range s.Sources {
+		taxYear, ages := s.labels(year)
+		for _, source := range sources {
 			t := Transaction{
-				Year:      year,
-				Source:    source.Name,
-				Amount:    withdrawn[source],    // inc tax
-				Tax:       taxWithdrawn[source], // tax
-				TaxRaised: taxRaised[source],    // tax raised
-				Balance:   source.Balance(),
+				Year:       year,
+				TaxYear:    taxYear,
+				Ages:       ages,
+				Source:     source.Name,
+				Kind:       source.Kind(),
+				Amount:     withdrawn[source],    // inc tax
+				Tax:        taxWithdrawn[source], // tax
+				TaxRaised:  taxRaised[source],    // tax raised
+				Balance:    source.Balance(),
+				PriceIndex: priceIndex,
+				Flows:      source.Flows(),
+
+				PropertyValue: source.propertyValue,
+				PropertyDebt:  source.propertyDebt,
 			}
 			transactions = append(transactions, t)
 			source.EndYear(year)
@@ -163,11 +453,32 @@ func (s *DrawScenario) Iterate(years int, year1AnnualIncome int) DrawHistory {
 		for _, iv := range s.InflationLinkedVariables {
 			*iv = int64(float64(*iv) * (1 + s.Rates.AnnualInflationRate/100))
 		}
+		priceIndex *= 1 + s.Rates.AnnualInflationRate/100
 
+		stop := false
 		if need > 0 {
-			fmt.Println("Not enough funds in year ", year, " need ", need)
+			events = append(events, Shortfall{Year: year, Amount: need})
+			ledger.add(LedgerEntry{Year: year, Kind: ShortfallEntry, Amount: need, Note: "need which could not be met"})
+			stop = !s.ContinueAfterShortfall
+		}
+		if (stop || year == years) && unpaidTax > 0 {
+			events = append(events, TaxDueAfterHorizon{Year: year, Amount: unpaidTax})
+		}
+		if stop {
 			break
 		}
 	}
-	return transactions
+	return DrawResult{History: transactions, Events: events, Ledger: ledger}, nil
+}
+
+// taxNote explains how a withdrawal of amount is assessed in the tax account ta, which may be nil.
+func taxNote(ta *TaxAccount, amount int64) string {
+	if ta == nil {
+		return "not taxable"
+	}
+	bands := []string{}
+	for _, b := range ta.Bands(amount) {
+		bands = append(bands, fmt.Sprintf("%d at %g%%", b.Amount, b.Rate))
+	}
+	return fmt.Sprintf("assessed by %s on %d: %s", ta.Name, amount, strings.Join(bands, ", "))
 }
@@ -0,0 +1,106 @@
+package drawdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIterateContextStopsWhenDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := defineTestScenario(t).IterateContext(ctx, 30, 30000)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if len(result.History) != 0 {
+		t.Errorf("ran %d transactions after the context was done", len(result.History))
+	}
+}
+
+func TestTaxRaisedInTheLastYearFallsDueAfterTheRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		income   int
+		lastYear int
+	}{
+		{"completed", 30000, 10},
+		{"stopped by a shortfall", 90000, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := defineTestScenario(t).Iterate(10, tt.income)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lastYear := tt.lastYear
+			if sfs := result.Shortfalls(); lastYear == 0 && len(sfs) > 0 {
+				lastYear = sfs[0].Year
+			}
+			due := []TaxDueAfterHorizon{}
+			for _, e := range result.Events {
+				switch e := e.(type) {
+				case UnpaidTax:
+					t.Errorf("unexpected %v", e)
+				case TaxDueAfterHorizon:
+					due = append(due, e)
+				}
+			}
+			if len(due) != 1 || due[0].Year != lastYear || due[0].Amount <= 0 {
+				t.Errorf("got %v, want tax falling due after year %d", due, lastYear)
+			}
+		})
+	}
+}
+
+func TestIterateErrorsAreInvalidScenarios(t *testing.T) {
+	b := NewBuilder()
+	savings := b.AddSource(NewSavingsAccount("Savings", 1000, &b.Rates().SavingsGrowthRate))
+	b.DrawFrom(savings, NewSavingsAccount("Not added", 1000, &b.Rates().SavingsGrowthRate))
+	_, err := b.s.Iterate(5, 1000)
+	var ps Problems
+	if !errors.As(err, &ps) || len(ps) != 1 {
+		t.Fatalf("got %v, want one problem", err)
+	}
+	var is *InvalidScenario
+	if !errors.As(err, &is) || is.Source != "Not added" {
+		t.Errorf("errors.As found %v, want the problem with the source not added", is)
+	}
+}
+
+func TestTaxIsPaidFromTheTaxPaymentSequence(t *testing.T) {
+	result := ledgerTestResult(t)
+	// The tax of 4,486 raised on the pension in year 1 is paid from savings in year 2.
+	for _, tr := range result.History {
+		want := int64(0)
+		if tr.Year == 2 && tr.Source == "Savings" {
+			want = 4486
+		}
+		if tr.Tax != want {
+			t.Errorf("year %d %s: tax paid %d, want %d", tr.Year, tr.Source, tr.Tax, want)
+		}
+	}
+	if s := result.History.Summary(); s.TotalTaxPaid != 4486 {
+		t.Errorf("total tax paid %d, want 4486", s.TotalTaxPaid)
+	}
+
+	// With nothing in the tax payment sequence the tax is added to the next year's need.
+	s, err := DefineWith(func(b *Builder) {
+		incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+		pension := b.AddSource(NewPensionAccount("Pension", 200000, &b.Rates().InvestmentGrowthRate), incomeTax)
+		b.DrawFrom(pension)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = s.Iterate(2, 40000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := result.Events[0]; u != (UnpaidTax{Year: 2, Amount: 5486}) {
+		t.Errorf("got %v, want the tax raised in year 1 unpaid in year 2", u)
+	}
+	if a := result.History[1].Amount; a != 45486 {
+		t.Errorf("drew %d in year 2, want the need and last year's tax", a)
+	}
+}
@@ -0,0 +1,134 @@
+package drawdown
+
+import (
+	"fmt"
+)
+
+// Event is something noteworthy that happened in a year while iterating a scenario.
+type Event interface {
+	EventYear() int
+	String() string
+}
+
+// Shortfall records need which could not be met from the sources in a year.
+type Shortfall struct {
+	Year   int
+	Amount int64
+}
+
+func (e Shortfall) EventYear() int {
+	return e.Year
+}
+
+func (e Shortfall) String() string {
+	return fmt.Sprintf("year %d: not enough funds, need %d unmet", e.Year, e.Amount)
+}
+
+// UnpaidTax records tax raised in the year before which the tax payment sequence could not pay.
+// It is added to the year's need instead.
+type UnpaidTax struct {
+	Year   int
+	Amount int64
+}
+
+func (e UnpaidTax) EventYear() int {
+	return e.Year
+}
+
+func (e UnpaidTax) String() string {
+	return fmt.Sprintf("year %d: tax of %d unpaid", e.Year, e.Amount)
+}
+
+// TaxDueAfterHorizon records the tax raised in the last year run.
+// Tax is paid the following year, so this tax falls due after the end of the run;
+// unlike UnpaidTax, it is not tax which could not be paid.
+type TaxDueAfterHorizon struct {
+	Year   int
+	Amount int64
+}
+
+func (e TaxDueAfterHorizon) EventYear() int {
+	return e.Year
+}
+
+func (e TaxDueAfterHorizon) String() string {
+	return fmt.Sprintf("year %d: tax of %d raised, falling due after the end of the run", e.Year, e.Amount)
+}
+
+// InvalidScenario is the error returned when a scenario cannot be simulated,
+// for example because a source would be left with a negative balance
+// or a tax regime does not cover the amount to be taxed.
+type InvalidScenario struct {
+	Year   int    // The year in which the problem arose, or 0 if it is not tied to a year.
+	Source string // The name of the source concerned, if any.
+	Reason string
+}
+
+func (e *InvalidScenario) Error() string {
+	msg := "invalid scenario"
+	if e.Year != 0 {
+		msg += fmt.Sprintf(" in year %d", e.Year)
+	}
+	if e.Source != "" {
+		msg += fmt.Sprintf(" (%s)", e.Source)
+	}
+	return msg + ": " + e.Reason
+}
+
+// DrawResult is the outcome of iterating a scenario.
+type DrawResult struct {
+	History DrawHistory
+	Events  []Event // In increasing year order.
+	Ledger  Ledger  // Every movement of money, explained.
+}
+
+// Real returns the result with every amount, including those of the events and the ledger,
+// deflated to today's (year 1) money.
+func (r DrawResult) Real() DrawResult {
+	priceIndex := map[int]float64{}
+	for _, t := range r.History {
+		priceIndex[t.Year] = t.PriceIndex
+	}
+	deflate := func(year int, a int64) int64 {
+		if pi := priceIndex[year]; pi != 0 {
+			return int64(float64(a) / pi)
+		}
+		return a
+	}
+	deflated := DrawResult{
+		History: r.History.Real(),
+		Events:  make([]Event, len(r.Events)),
+		Ledger:  make(Ledger, len(r.Ledger)),
+	}
+	for i, e := range r.Events {
+		switch e := e.(type) {
+		case Shortfall:
+			e.Amount = deflate(e.Year, e.Amount)
+			deflated.Events[i] = e
+		case UnpaidTax:
+			e.Amount = deflate(e.Year, e.Amount)
+			deflated.Events[i] = e
+		case TaxDueAfterHorizon:
+			e.Amount = deflate(e.Year, e.Amount)
+			deflated.Events[i] = e
+		default:
+			deflated.Events[i] = e
+		}
+	}
+	for i, le := range r.Ledger {
+		le.Amount = deflate(le.Year, le.Amount)
+		deflated.Ledger[i] = le
+	}
+	return deflated
+}
+
+// Shortfalls returns the Shortfall events of the result.
+func (r DrawResult) Shortfalls() []Shortfall {
+	shortfalls := []Shortfall{}
+	for _, e := range r.Events {
+		if sf, ok := e.(Shortfall); ok {
+			shortfalls = append(shortfalls, sf)
+		}
+	}
+	return shortfalls
+}
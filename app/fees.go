@@ -0,0 +1,54 @@
+package drawdown
+
+// FeeSchedule describes the charges levied on a source each year, evaluated on its balance.
+// A source with a fee schedule is charged by its schedule instead of the scenario's PlatformChargeRate.
+type FeeSchedule struct {
+	Tiers         []FeeTier // Tiered platform charges. Subsequent upper values must be strictly increasing.
+	AnnualFlatFee int64     // A fixed platform charge each year.
+	AnnualCap     int64     // The maximum platform charge (tiered plus flat) each year, or 0 for no cap.
+	FundOCFPct    float64   // The ongoing charges of the funds held, which are not subject to the cap.
+}
+
+// FeeTier contains a percentage charged each year on the part of a balance up to an upper bound.
+// Percentages are expressed as, for example, 0.25 for 0.25%.
+type FeeTier struct {
+	upper int64
+	pct   float64
+}
+
+func NewFeeTier(upper int64, pct float64) FeeTier {
+	return FeeTier{upper: upper, pct: pct}
+}
+
+// AnnualFee returns the fees charged for a year on the given balance.
+func (fs *FeeSchedule) AnnualFee(balance int64) int64 {
+	platform := fs.AnnualFlatFee
+	lastUpper := int64(0)
+	for _, t := range fs.Tiers {
+		if balance <= lastUpper {
+			break
+		}
+		inTier := min(balance, t.upper) - lastUpper
+		platform += int64(float64(inTier) * t.pct / 100)
+		lastUpper = t.upper
+	}
+	if fs.AnnualCap > 0 {
+		platform = min(platform, fs.AnnualCap)
+	}
+	return platform + int64(float64(balance)*fs.FundOCFPct/100)
+}
+
+// WithFees attaches a fee schedule to the source and returns the source.
+func (is *Source) WithFees(fs *FeeSchedule) *Source {
+	is.fees = fs
+	return is
+}
+
+// fee returns the fee due on the source for one of the periods into which a year is divided,
+// using the source's fee schedule or, if it has none, the given platform charge rate.
+func (is *Source) fee(platformChargePct float64, periods int) int64 {
+	if is.fees != nil {
+		return is.fees.AnnualFee(is.balance) / int64(periods)
+	}
+	return int64(float64(is.PlatformChargeBalance()) * platformChargePct / 100 / float64(periods))
+}
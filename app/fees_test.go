@@ -0,0 +1,55 @@
+package drawdown
+
+import "testing"
+
+func TestFeeScheduleAnnualFee(t *testing.T) {
+	tiers := []FeeTier{
+		NewFeeTier(250000, 0.25),
+		NewFeeTier(1000000, 0.10),
+		NewFeeTier(HighUpperBound, 0),
+	}
+	tests := []struct {
+		name    string
+		fs      FeeSchedule
+		balance int64
+		want    int64
+	}{
+		{"first tier", FeeSchedule{Tiers: tiers}, 100000, 250},
+		{"two tiers", FeeSchedule{Tiers: tiers}, 400000, 625 + 150},
+		{"free above the top tier", FeeSchedule{Tiers: tiers}, 2000000, 625 + 750},
+		{"flat fee", FeeSchedule{Tiers: tiers, AnnualFlatFee: 120}, 100000, 120 + 250},
+		{"flat fee on an empty balance", FeeSchedule{AnnualFlatFee: 120}, 0, 120},
+		{"capped", FeeSchedule{Tiers: tiers, AnnualCap: 500}, 400000, 500},
+		{"cap includes the flat fee", FeeSchedule{Tiers: tiers, AnnualFlatFee: 120, AnnualCap: 300}, 100000, 300},
+		{"under the cap", FeeSchedule{Tiers: tiers, AnnualCap: 500}, 100000, 250},
+		{"cap excludes the fund charges", FeeSchedule{Tiers: tiers, AnnualCap: 500, FundOCFPct: 0.2}, 400000, 500 + 800},
+		{"fund charges only", FeeSchedule{FundOCFPct: 0.15}, 100000, 150},
+	}
+	for _, tt := range tests {
+		if got := tt.fs.AnnualFee(tt.balance); got != tt.want {
+			t.Errorf("%s: AnnualFee(%d) = %d, want %d", tt.name, tt.balance, got, tt.want)
+		}
+	}
+}
+
+func TestSourceFee(t *testing.T) {
+	rate := 0.0
+	tests := []struct {
+		name    string
+		source  *Source
+		periods int
+		want    int64
+	}{
+		{"platform charge rate", NewSavingsAccount("Savings", 100000, &rate), 1, 250},
+		{"platform charge rate monthly", NewSavingsAccount("Savings", 100000, &rate), 12, 20},
+		{"fee schedule instead of the rate", NewSavingsAccount("Savings", 100000, &rate).WithFees(&FeeSchedule{AnnualFlatFee: 120}), 1, 120},
+		{"fee schedule monthly", NewSavingsAccount("Savings", 100000, &rate).WithFees(&FeeSchedule{AnnualFlatFee: 120}), 12, 10},
+		{"not charged", NewStatePension("State Pension", 10000, 0, 1), 1, 0},
+	}
+	for _, tt := range tests {
+		tt.source.StartYear(1)
+		if got := tt.source.fee(0.25, tt.periods); got != tt.want {
+			t.Errorf("%s: fee = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package drawdown
+
+import "testing"
+
+// defineEverythingScenario defines a scenario with a source of every kind: earnings, rent, the state pension,
+// savings, an ISA with a fee schedule, a pension, a loan paid off early, and a home with a lifetime mortgage
+// and an equity release facility.
+func defineEverythingScenario(t *testing.T) *DrawScenario {
+	t.Helper()
+	s, err := DefineWith(func(b *Builder) {
+		r := b.Rates()
+		incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+		cgt := b.TaxWith("Capital Gains Tax", NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(HighUpperBound, 24)}))
+		ni := b.NIWith(class1NIRegime())
+		cash := b.AddSource(NewSavingsAccount("Cash", 20000, &r.SavingsGrowthRate))
+		isa := b.AddSource(NewInvestmentAccount("ISA", 50000, &r.InvestmentGrowthRate).WithFees(&FeeSchedule{
+			Tiers:         []FeeTier{NewFeeTier(250000, 0.25), NewFeeTier(HighUpperBound, 0.1)},
+			AnnualFlatFee: 50,
+			FundOCFPct:    0.2,
+		}))
+		pension := b.AddSource(NewPensionAccount("Pension", 150000, &r.InvestmentGrowthRate), incomeTax)
+		earnings := b.AddSource(NewEarnings("Earnings", 15000, &r.AnnualInflationRate, 1, 1, 3, ni), incomeTax)
+		flat := b.AddSource(NewRentalProperty("Flat", rentalTerms(cash, cgt)), incomeTax)
+		statePension := b.AddSource(NewStatePension("State Pension", 11000, 2.5, 3), incomeTax)
+		loan := b.AddSource(NewLoan("Loan", 30000, 5, 8))
+		home := NewHome("Home", 400000, &r.HousePriceGrowthRate)
+		home.ReleaseEquity(4, 50000, 6, cash)
+		facility := b.AddSource(home.EquityReleaseFacility("Facility", 6, 40, 6))
+		b.Home(home)
+		b.DrawFrom(earnings, flat, statePension, cash, isa, pension, facility).
+			PayTaxFrom(cash, pension).
+			InYear(5, func(year int, need int64, s *DrawScenario) {
+				PayOff(loan, cash)
+			})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFlowsReconcileEveryYear(t *testing.T) {
+	rates := DrawRates{InvestmentGrowthRate: 5, SavingsGrowthRate: 3, AnnualInflationRate: 2.5, PlatformChargeRate: 0.3, TaxBandAnnualPctIncrease: 1, HousePriceGrowthRate: 3}
+	scenarios := []struct {
+		name   string
+		define func(t *testing.T) *DrawScenario
+	}{
+		{"test", defineTestScenario},
+		{"everything", defineEverythingScenario},
+	}
+	for _, sc := range scenarios {
+		for _, monthly := range []bool{false, true} {
+			name := sc.name + " yearly"
+			if monthly {
+				name = sc.name + " monthly"
+			}
+			t.Run(name, func(t *testing.T) {
+				s := sc.define(t).WithRates(rates).WithContinueAfterShortfall(true)
+				iterate := s.Iterate
+				if monthly {
+					iterate = s.IterateMonthly
+				}
+				result, err := iterate(15, 40000)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.History) == 0 {
+					t.Fatal("no transactions")
+				}
+				closing := map[string]int64{} // The closing balance of each source in the year before.
+				for _, tr := range result.History {
+					f := tr.Flows
+					if got := f.OpeningBalance + f.Growth - f.Fees + f.Deposits - f.Withdrawals; got != tr.Balance {
+						t.Errorf("year %d %s: %d + %d - %d + %d - %d = %d, want the closing balance %d",
+							tr.Year, tr.Source, f.OpeningBalance, f.Growth, f.Fees, f.Deposits, f.Withdrawals, got, tr.Balance)
+					}
+					if before, ok := closing[tr.Source]; ok && f.OpeningBalance != before {
+						t.Errorf("year %d %s: opening balance %d, want last year's closing balance %d", tr.Year, tr.Source, f.OpeningBalance, before)
+					}
+					closing[tr.Source] = tr.Balance
+				}
+			})
+		}
+	}
+}
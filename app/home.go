@@ -0,0 +1,141 @@
+package drawdown
+
+import (
+	"fmt"
+)
+
+// Home represents the main residence.
+// Its value grows with the house price rate and it counts towards the estate,
+// but it is never drawn on by the DrawSequence.
+// Equity can be released from it by downsizing or with a lifetime mortgage.
+// The balance of the underlying Source is the equity: the value less any lifetime mortgage debt.
+type Home struct {
+	*Source
+	value      int64
+	loans      []*lifetimeMortgage
+	facilities []*equityReleaseFacility
+	events     map[int][]func()
+}
+
+// A lifetimeMortgage is an amount borrowed against the home on which interest rolls up until it is repaid.
+type lifetimeMortgage struct {
+	debt    int64
+	ratePct float64
+}
+
+type equityReleaseFacility struct {
+	source   *Source
+	fromYear int
+	limitPct float64
+}
+
+// NewHome creates the main residence.
+// Year1Value is the value at the start of the first year.
+// AnnualPctIncrease is the house price growth rate. (For example, 2.0 for 2% growth per year).
+func NewHome(name string, year1Value int64, annualPctIncrease *float64) *Home {
+	h := &Home{
+		Source: &Source{
+			Name: name,
+			kind: Illiquid,
+		},
+		value:  year1Value,
+		events: map[int][]func(){},
+	}
+	h.setBalance(year1Value)
+	h.startYear = func(year int) {
+		if year > 1 {
+			before := h.value
+			h.value = int64(float64(h.value) * (1 + *annualPctIncrease/100))
+			h.record(GrowthEntry, h.value-before, fmt.Sprintf("house price growth at %.2f%% a year", *annualPctIncrease))
+			for _, lm := range h.loans {
+				interest := int64(float64(lm.debt) * lm.ratePct / 100)
+				lm.debt += interest
+				h.record(InterestEntry, interest, fmt.Sprintf("lifetime mortgage interest rolled up at %.2f%%", lm.ratePct))
+			}
+		}
+		h.growTo(max(0, h.value-h.Debt()))
+		for _, e := range h.events[year] {
+			e()
+		}
+		h.updateEquity()
+		for _, f := range h.facilities {
+			available := int64(0)
+			if year >= f.fromYear {
+				available = max(0, int64(float64(h.value)*f.limitPct/100)-h.Debt())
+			}
+			f.source.setBalance(available)
+		}
+	}
+	// The home is never drawn on directly.
+	h.makeWithdrawal = func(amount int64) []SourceAmount {
+		return []SourceAmount{{h.Source, 0}}
+	}
+	return h
+}
+
+// Value returns the current value of the home.
+func (h *Home) Value() int64 {
+	return h.value
+}
+
+// Debt returns the amount owed under lifetime mortgages, including rolled-up interest.
+func (h *Home) Debt() int64 {
+	debt := int64(0)
+	for _, lm := range h.loans {
+		debt += lm.debt
+	}
+	return debt
+}
+
+func (h *Home) borrow(amount int64, ratePct float64) {
+	h.loans = append(h.loans, &lifetimeMortgage{debt: amount, ratePct: ratePct})
+	h.updateEquity()
+}
+
+// updateEquity sets the balance to the value less the debt.
+// Lifetime mortgages carry a no negative equity guarantee so the balance is never less than zero.
+func (h *Home) updateEquity() {
+	h.setBalance(max(0, h.value-h.Debt()))
+}
+
+// Downsize sells the home at the start of the given year and buys one worth newValue.
+// Any lifetime mortgage is repaid and the equity released, less costsPct of the sale price,
+// is deposited tax-free into the given source.
+func (h *Home) Downsize(year int, newValue int64, costsPct float64, to *Source) {
+	h.events[year] = append(h.events[year], func() {
+		costs := int64(float64(h.value) * costsPct / 100)
+		released := max(0, h.value-newValue-costs-h.Debt())
+		h.loans = nil
+		h.value = newValue
+		to.Deposit(released)
+		to.record(DepositEntry, released, "equity released by downsizing from "+h.Name)
+	})
+}
+
+// ReleaseEquity takes out a lifetime mortgage for a lump sum at the start of the given year
+// and deposits it into the given source.
+// Interest at ratePct rolls up on the amount borrowed until the home is sold.
+func (h *Home) ReleaseEquity(year int, amount int64, ratePct float64, to *Source) {
+	h.events[year] = append(h.events[year], func() {
+		h.borrow(amount, ratePct)
+		to.Deposit(amount)
+		to.record(DepositEntry, amount, "lifetime mortgage on "+h.Name)
+	})
+}
+
+// EquityReleaseFacility returns a source which draws on a lifetime mortgage drawdown facility from the given year.
+// The facility allows up to limitPct of the home's value to be borrowed, less any debt already owed.
+// Interest at ratePct rolls up on each amount drawn until the home is sold.
+func (h *Home) EquityReleaseFacility(name string, fromYear int, limitPct float64, ratePct float64) *Source {
+	is := &Source{
+		Name: name,
+		kind: Credit,
+	}
+	is.makeWithdrawal = func(amount int64) []SourceAmount {
+		sas := is.reduceBalance(amount)
+		h.borrow(totalSourceAmount(sas), ratePct)
+		return sas
+	}
+	h.facilities = append(h.facilities, &equityReleaseFacility{source: is, fromYear: fromYear, limitPct: limitPct})
+	return is
+}
@@ -0,0 +1,91 @@
+package drawdown
+
+import "testing"
+
+func TestHomeEquityReleaseAndDownsizing(t *testing.T) {
+	type year struct {
+		value, debt, equity, cash int64
+	}
+	tests := []struct {
+		name  string
+		value int64
+		hpg   float64
+		plan  func(h *Home, cash *Source)
+		want  []year // From year 1.
+	}{
+		{
+			name:  "growth",
+			value: 300000,
+			hpg:   2,
+			plan:  func(h *Home, cash *Source) {},
+			want:  []year{{300000, 0, 300000, 0}, {306000, 0, 306000, 0}, {312120, 0, 312120, 0}},
+		},
+		{
+			// Interest at 6% rolls up on the 50,000 borrowed in year 2.
+			// In year 4 the home, now worth 318,362, is sold for a 200,000 one:
+			// the costs of 2% are 6,367 and the debt of 56,180 is repaid, releasing 55,815.
+			name:  "release then downsize",
+			value: 300000,
+			hpg:   2,
+			plan: func(h *Home, cash *Source) {
+				h.ReleaseEquity(2, 50000, 6, cash)
+				h.Downsize(4, 200000, 2, cash)
+			},
+			want: []year{
+				{300000, 0, 300000, 0},
+				{306000, 50000, 256000, 50000},
+				{312120, 53000, 259120, 50000},
+				{200000, 0, 200000, 105815},
+				{204000, 0, 204000, 105815},
+			},
+		},
+		{
+			// The debt rolls up beyond the value, but the no negative equity guarantee keeps the equity at zero.
+			name:  "no negative equity",
+			value: 100000,
+			hpg:   0,
+			plan: func(h *Home, cash *Source) {
+				h.ReleaseEquity(1, 90000, 10, cash)
+			},
+			want: []year{{100000, 90000, 10000, 90000}, {100000, 99000, 1000, 90000}, {100000, 108900, 0, 90000}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cash := NewSavingsAccount("Cash", 0, new(float64))
+			h := NewHome("Home", tt.value, &tt.hpg)
+			tt.plan(h, cash)
+			for i, want := range tt.want {
+				h.StartYear(i + 1)
+				got := year{h.Value(), h.Debt(), h.Balance(), cash.Balance()}
+				if got != want {
+					t.Errorf("year %d: got %+v, want %+v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHomeEquityReleaseFacility(t *testing.T) {
+	hpg := 2.0
+	h := NewHome("Home", 300000, &hpg)
+	facility := h.EquityReleaseFacility("Drawdown lifetime mortgage", 2, 50, 5)
+
+	h.StartYear(1)
+	if facility.Balance() != 0 {
+		t.Errorf("year 1: %d available before the facility opens", facility.Balance())
+	}
+	h.StartYear(2)
+	if facility.Balance() != 153000 { // Half of 306,000.
+		t.Errorf("year 2: %d available, want 153000", facility.Balance())
+	}
+	got := totalSourceAmount(facility.Withdraw(20000))
+	if got != 20000 || h.Debt() != 20000 || h.Balance() != 286000 || facility.Balance() != 133000 {
+		t.Errorf("after drawing 20000: drew %d, debt %d, equity %d, available %d", got, h.Debt(), h.Balance(), facility.Balance())
+	}
+	h.StartYear(3)
+	// Half of 312,120 less the debt of 21,000 after a year's interest.
+	if facility.Balance() != 135060 {
+		t.Errorf("year 3: %d available, want 135060", facility.Balance())
+	}
+}
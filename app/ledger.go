@@ -0,0 +1,80 @@
+package drawdown
+
+import (
+	"fmt"
+	"io"
+)
+
+// EntryKind describes the kind of movement recorded by a LedgerEntry.
+type EntryKind string
+
+const (
+	NeedEntry           EntryKind = "Need"            // An amount added to the year's need.
+	GrowthEntry         EntryKind = "Growth"          // Growth applied to a balance.
+	IncomeEntry         EntryKind = "Income"          // Income, such as the state pension, made available for the year.
+	PlatformChargeEntry EntryKind = "Platform charge" // A platform charge.
+	WithdrawalEntry     EntryKind = "Withdrawal"      // A withdrawal made to meet the need.
+	TransferEntry       EntryKind = "Transfer"        // Money moved between sources by an action.
+	DepositEntry        EntryKind = "Deposit"         // Money paid into a source by an event, such as a sale.
+	TaxEntry            EntryKind = "Tax"             // Tax assessed.
+	InterestEntry       EntryKind = "Interest"        // Interest charged on a liability.
+	RepaymentEntry      EntryKind = "Repayment"       // A repayment of a liability.
+	ShortfallEntry      EntryKind = "Shortfall"       // Need which could not be met.
+)
+
+// A LedgerEntry explains a single movement of money during a year.
+type LedgerEntry struct {
+	Year   int
+	Source string // The source concerned, or "" for the scenario as a whole.
+	Kind   EntryKind
+	Amount int64
+	Note   string // Why the movement happened.
+}
+
+// Ledger records every movement of money during an iteration, in the order in which they happened.
+type Ledger []LedgerEntry
+
+func (l *Ledger) add(e LedgerEntry) {
+	*l = append(*l, e)
+}
+
+// Year returns the entries for the given year.
+func (l Ledger) Year(year int) Ledger {
+	entries := Ledger{}
+	for _, e := range l {
+		if e.Year == year {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Explain writes a readable narrative of the given year to w.
+func (l Ledger) Explain(w io.Writer, year int) error {
+	entries := l.Year(year)
+	if len(entries) == 0 {
+		_, err := fmt.Fprintf(w, "Year %d: nothing recorded\n", year)
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Year %d\n", year); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		source := e.Source
+		if source == "" {
+			source = "-"
+		}
+		if _, err := fmt.Fprintf(w, "  %-16s %-28s %12d  %s\n", e.Kind, source, e.Amount, e.Note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record adds an entry for the source to the ledger of the iteration it is taking part in, if any.
+func (is *Source) record(kind EntryKind, amount int64, note string) {
+	if is.ledger == nil {
+		return
+	}
+	is.ledger.add(LedgerEntry{Year: is.year, Source: is.Name, Kind: kind, Amount: amount, Note: note})
+}
@@ -0,0 +1,70 @@
+package drawdown
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ledgerTestResult runs two years of a scenario which draws on savings, up to 5,000 a year, and then on a pension.
+func ledgerTestResult(t *testing.T) DrawResult {
+	t.Helper()
+	s, err := DefineWith(func(b *Builder) {
+		r := b.Rates()
+		incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+		savings := b.AddSource(NewSavingsAccount("Savings", 50000, &r.SavingsGrowthRate))
+		pension := b.AddSource(NewPensionAccount("Pension", 200000, &r.InvestmentGrowthRate), incomeTax)
+		limit := int64(5000)
+		b.DrawFrom(Seq(&limit, savings), pension).PayTaxFrom(savings)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.WithRates(DrawRates{InvestmentGrowthRate: 5, SavingsGrowthRate: 10, PlatformChargeRate: 1}).Iterate(2, 40000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestLedgerYear(t *testing.T) {
+	got := ledgerTestResult(t).Ledger.Year(2)
+	want := Ledger{
+		{2, "", NeedEntry, 40000, "income of 40000 inflated at 0.00% a year"},
+		// Year 1 left 44,500 in savings and 163,000 in the pension, after charges of 1%.
+		{2, "Savings", GrowthEntry, 4450, "growth at 10.00% a year"},
+		{2, "Pension", GrowthEntry, 8150, "growth at 5.00% a year"},
+		// 5,000 from savings and 35,000 from the pension in year 1 raised 22,430 at 20%, paid from savings.
+		{2, "Savings", WithdrawalEntry, 4486, "last year's tax paid by Savings"},
+		{2, "Savings", PlatformChargeEntry, 444, "1.00% a year on a balance of 44464"},
+		{2, "Pension", PlatformChargeEntry, 1711, "1.00% a year on a balance of 171150"},
+		// Savings are drawn up to the limit of the Seq, and the pension meets the rest of the need.
+		{2, "Savings", WithdrawalEntry, 5000, "drawn by Seq Savings"},
+		{2, "Pension", WithdrawalEntry, 35000, "drawn by Pension"},
+		{2, "Pension", TaxEntry, 4486, "assessed by Income Tax on 35000: 12570 at 0%, 22430 at 20%"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestLedgerExplain(t *testing.T) {
+	l := ledgerTestResult(t).Ledger
+	var b bytes.Buffer
+	if err := l.Explain(&b, 2); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	if len(lines) != 1+len(l.Year(2)) || lines[0] != "Year 2" {
+		t.Fatalf("got %q, want a heading and a line for each entry", lines)
+	}
+	if f := strings.Fields(lines[7]); strings.Join(f[:4], " ") != "Withdrawal Savings 5000 drawn" {
+		t.Errorf("got %q for the withdrawal from savings", lines[7])
+	}
+
+	b.Reset()
+	if err := l.Explain(&b, 3); err != nil || b.String() != "Year 3: nothing recorded\n" {
+		t.Errorf("got %q, %v for a year which was not run", b.String(), err)
+	}
+}
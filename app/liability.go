@@ -0,0 +1,45 @@
+package drawdown
+
+import (
+	"fmt"
+	"math"
+)
+
+// NewLoan creates a liability source for a repayment mortgage or loan.
+// InitialBalance is the amount outstanding at the start of the first year.
+// AnnualPctRate is the interest rate. (For example, 5.0 for 5% per year).
+// The loan is amortised with equal annual repayments so that it is repaid by the end of year termYears.
+// Each year's repayment is added to the need and the balance is the amount outstanding after that repayment.
+func NewLoan(name string, initialBalance int64, annualPctRate float64, termYears int) *Source {
+	is := &Source{
+		Name:              name,
+		kind:              Liability,
+		hasPlatformCharge: false,
+	}
+	is.setBalance(initialBalance)
+	is.startYear = func(year int) {
+		is.repaymentDue = 0
+		remaining := termYears - year + 1 // Repayments remaining including this year's.
+		if is.balance == 0 || remaining <= 0 {
+			return
+		}
+		rate := annualPctRate / 100
+		interest := int64(float64(is.balance) * rate)
+		repayment := is.balance + interest // The final repayment clears the loan.
+		if remaining > 1 && rate > 0 {
+			repayment = int64(float64(is.balance) * rate / (1 - math.Pow(1+rate, -float64(remaining))))
+		} else if remaining > 1 {
+			repayment = is.balance / int64(remaining)
+		}
+		is.repaymentDue = repayment
+		is.growTo(is.balance + interest)
+		is.setBalance(max(0, is.balance-repayment))
+		is.record(InterestEntry, interest, fmt.Sprintf("interest at %.2f%%", annualPctRate))
+		is.record(RepaymentEntry, repayment, fmt.Sprintf("scheduled repayment, %d of %d years remaining", remaining, termYears))
+	}
+	// A liability is never drawn on.
+	is.makeWithdrawal = func(amount int64) []SourceAmount {
+		return []SourceAmount{{is, 0}}
+	}
+	return is
+}
@@ -0,0 +1,135 @@
+package drawdown
+
+import "testing"
+
+func TestLoanAmortisation(t *testing.T) {
+	type year struct {
+		interest, repayment, balance int64
+	}
+	tests := []struct {
+		name    string
+		balance int64
+		ratePct float64
+		term    int
+		want    []year // From year 1, running past the end of the term.
+	}{
+		{
+			// Equal repayments of 3,672 a year, with the last clearing the balance after interest.
+			name:    "interest bearing",
+			balance: 10000,
+			ratePct: 5,
+			term:    3,
+			want:    []year{{500, 3672, 6828}, {341, 3672, 3497}, {174, 3671, 0}, {0, 0, 0}},
+		},
+		{
+			name:    "interest free",
+			balance: 9000,
+			ratePct: 0,
+			term:    3,
+			want:    []year{{0, 3000, 6000}, {0, 3000, 3000}, {0, 3000, 0}, {0, 0, 0}},
+		},
+		{
+			name:    "one year term",
+			balance: 5000,
+			ratePct: 4,
+			term:    1,
+			want:    []year{{200, 5200, 0}, {0, 0, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := NewLoan("Loan", tt.balance, tt.ratePct, tt.term)
+			for i, want := range tt.want {
+				loan.openYear()
+				loan.StartYear(i + 1)
+				f := loan.Flows()
+				got := year{f.Growth, loan.RepaymentDue(), loan.Balance()}
+				if got != want {
+					t.Errorf("year %d: got %+v, want %+v", i+1, got, want)
+				}
+				if f.OpeningBalance+f.Growth-f.Withdrawals != loan.Balance() {
+					t.Errorf("year %d: flows %+v do not reconcile with the balance %d", i+1, f, loan.Balance())
+				}
+			}
+			if loan.Kind() != Liability || totalSourceAmount(loan.Withdraw(1000)) != 0 {
+				t.Error("a loan was drawn on")
+			}
+		})
+	}
+}
+
+func TestPayOff(t *testing.T) {
+	tests := []struct {
+		name           string
+		cash           int64
+		wantRepaid     int64
+		wantRepayments []int64 // The scheduled repayments in the following years.
+	}{
+		// The whole 6,828 outstanding after the first year's repayment is repaid, ending the loan.
+		{"in full", 20000, 6828, []int64{0, 0}},
+		// 3,828 remains and is amortised over the remaining two years at 5%.
+		{"in part", 3000, 3000, []int64{2058, 2059}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := NewLoan("Loan", 10000, 5, 3)
+			cash := NewSavingsAccount("Cash", tt.cash, new(float64))
+			loan.openYear()
+			loan.StartYear(1)
+			if got := PayOff(loan, cash); got != tt.wantRepaid {
+				t.Errorf("repaid %d, want %d", got, tt.wantRepaid)
+			}
+			if loan.Balance() != 10000+500-3672-tt.wantRepaid || cash.Balance() != tt.cash-tt.wantRepaid {
+				t.Errorf("got loan balance %d and cash %d after repaying %d", loan.Balance(), cash.Balance(), tt.wantRepaid)
+			}
+			if f := loan.Flows(); f.Withdrawals != 3672+tt.wantRepaid {
+				t.Errorf("withdrawals %d, want the scheduled and early repayments of %d", f.Withdrawals, 3672+tt.wantRepaid)
+			}
+			for i, want := range tt.wantRepayments {
+				loan.openYear()
+				loan.StartYear(i + 2)
+				if loan.RepaymentDue() != want {
+					t.Errorf("year %d: repayment due %d, want %d", i+2, loan.RepaymentDue(), want)
+				}
+			}
+			if loan.Balance() != 0 {
+				t.Errorf("balance %d at the end of the term", loan.Balance())
+			}
+		})
+	}
+}
+
+func TestPayOffFromATaxablePension(t *testing.T) {
+	var repaid int64
+	s, err := DefineWith(func(b *Builder) {
+		incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+		savings := b.AddSource(NewSavingsAccount("Savings", 100000, &b.Rates().SavingsGrowthRate))
+		pension := b.AddSource(NewPensionAccount("Pension", 200000, &b.Rates().InvestmentGrowthRate), incomeTax)
+		loan := b.AddSource(NewLoan("Loan", 30000, 5, 8))
+		b.DrawFrom(savings).
+			PayTaxFrom(savings).
+			InYear(1, func(year int, need int64, s *DrawScenario) {
+				repaid = PayOff(loan, pension)
+			})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.Iterate(1, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The 26,859 outstanding after the scheduled repayment is taken from the pension and taxed as income,
+	// at 20% above the personal allowance of 12,570, though it does not meet the need.
+	for _, tr := range result.History {
+		if tr.Source != "Pension" {
+			continue
+		}
+		if repaid != 26859 || tr.TaxRaised != 2857 {
+			t.Errorf("repaid %d and raised tax of %d, want 26859 and 2857", repaid, tr.TaxRaised)
+		}
+		if tr.Amount != 0 {
+			t.Errorf("drew %d from the pension, want the repayment not to count towards the need", tr.Amount)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package drawdown
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// MonteCarlo describes many runs of a scenario, each with its own rates drawn at random
+// and its own seed for the scenario's random events, such as the start of care.
+// Each rate of a run is drawn from a normal distribution with the mean given in Mean
+// and the standard deviation given in StdDev, and holds for the whole of the run.
+// A rate with a standard deviation of zero is fixed at its mean.
+// The platform charge rate is never drawn below zero.
+type MonteCarlo struct {
+	Mean   DrawRates
+	StdDev DrawRates
+	Runs   int
+	Seed   int64 // The seed of the random draws, so that a set of runs can be repeated.
+}
+
+// Rates returns the rates of each run, rounded to hundredths of a percent.
+// The same seed always gives the same rates.
+func (mc MonteCarlo) Rates() []DrawRates {
+	r := rand.New(rand.NewSource(mc.Seed))
+	// Every rate is drawn, even with no deviation, so that the draws of one rate do not depend on the deviation of another.
+	draw := func(mean float64, sd float64) float64 {
+		return math.Round((mean+sd*r.NormFloat64())*100) / 100
+	}
+	rates := make([]DrawRates, max(0, mc.Runs))
+	for i := range rates {
+		m, sd := mc.Mean, mc.StdDev
+		rates[i] = DrawRates{
+			InvestmentGrowthRate:     draw(m.InvestmentGrowthRate, sd.InvestmentGrowthRate),
+			SavingsGrowthRate:        draw(m.SavingsGrowthRate, sd.SavingsGrowthRate),
+			AnnualInflationRate:      draw(m.AnnualInflationRate, sd.AnnualInflationRate),
+			PlatformChargeRate:       max(0, draw(m.PlatformChargeRate, sd.PlatformChargeRate)),
+			TaxBandAnnualPctIncrease: draw(m.TaxBandAnnualPctIncrease, sd.TaxBandAnnualPctIncrease),
+			HousePriceGrowthRate:     draw(m.HousePriceGrowthRate, sd.HousePriceGrowthRate),
+			CareCostInflationRate:    draw(m.CareCostInflationRate, sd.CareCostInflationRate),
+		}
+	}
+	return rates
+}
+
+// Seeds returns the seed of the random events of each run, which are drawn independently of the rates.
+// The same seed always gives the same seeds.
+func (mc MonteCarlo) Seeds() []int64 {
+	r := rand.New(rand.NewSource(^mc.Seed))
+	seeds := make([]int64, max(0, mc.Runs))
+	for i := range seeds {
+		seeds[i] = r.Int63()
+	}
+	return seeds
+}
+
+// Percentiles are the 10th, 50th (the median) and 90th percentiles of an amount over a set of runs.
+type Percentiles struct {
+	P10 int64
+	P50 int64
+	P90 int64
+}
+
+// MonteCarloSummary aggregates the summaries of the runs of a MonteCarlo.
+type MonteCarloSummary struct {
+	Runs                 int
+	RunsWithoutShortfall int     // The runs in which the need was met in every year.
+	SuccessPct           float64 // RunsWithoutShortfall as a percentage of Runs.
+	FinalBalance         Percentiles
+	RealFinalBalance     Percentiles
+	TotalShortfall       Percentiles
+}
+
+// SummarizeMonteCarlo aggregates the summaries of a set of runs.
+func SummarizeMonteCarlo(summaries []DrawSummary) MonteCarloSummary {
+	s := MonteCarloSummary{Runs: len(summaries)}
+	if s.Runs == 0 {
+		return s
+	}
+	finalBalance, realFinalBalance, totalShortfall := []int64{}, []int64{}, []int64{}
+	for _, rs := range summaries {
+		if rs.ShortfallYears == 0 {
+			s.RunsWithoutShortfall++
+		}
+		finalBalance = append(finalBalance, rs.FinalBalance)
+		realFinalBalance = append(realFinalBalance, rs.RealFinalBalance)
+		totalShortfall = append(totalShortfall, rs.TotalShortfall)
+	}
+	s.SuccessPct = 100 * float64(s.RunsWithoutShortfall) / float64(s.Runs)
+	s.FinalBalance = percentiles(finalBalance)
+	s.RealFinalBalance = percentiles(realFinalBalance)
+	s.TotalShortfall = percentiles(totalShortfall)
+	return s
+}
+
+// percentiles returns the percentiles of the amounts, which must not be empty, by the nearest rank.
+func percentiles(amounts []int64) Percentiles {
+	sorted := slices.Sorted(slices.Values(amounts))
+	at := func(pct int) int64 {
+		return sorted[max(0, (pct*len(sorted)+99)/100-1)]
+	}
+	return Percentiles{P10: at(10), P50: at(50), P90: at(90)}
+}
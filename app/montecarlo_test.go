@@ -0,0 +1,87 @@
+package drawdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMonteCarloRates(t *testing.T) {
+	mc := MonteCarlo{
+		Mean:   DrawRates{InvestmentGrowthRate: 4, AnnualInflationRate: 2.5, PlatformChargeRate: 0.1},
+		StdDev: DrawRates{InvestmentGrowthRate: 3, PlatformChargeRate: 1},
+		Runs:   500,
+		Seed:   7,
+	}
+	rates := mc.Rates()
+	if len(rates) != mc.Runs {
+		t.Fatalf("got %d runs, want %d", len(rates), mc.Runs)
+	}
+	if again := mc.Rates(); !reflect.DeepEqual(rates, again) {
+		t.Error("the same seed gave different rates")
+	}
+	mc.Seed = 8
+	if other := mc.Rates(); reflect.DeepEqual(rates, other) {
+		t.Error("a different seed gave the same rates")
+	}
+	varied := false
+	for _, r := range rates {
+		if r.AnnualInflationRate != 2.5 || r.SavingsGrowthRate != 0 {
+			t.Fatalf("a rate with no deviation was drawn as %+v", r)
+		}
+		if r.PlatformChargeRate < 0 {
+			t.Fatalf("a negative platform charge rate was drawn: %v", r.PlatformChargeRate)
+		}
+		varied = varied || r.InvestmentGrowthRate != rates[0].InvestmentGrowthRate
+	}
+	if !varied {
+		t.Error("a rate with a deviation was not varied")
+	}
+}
+
+func TestMonteCarloSeeds(t *testing.T) {
+	mc := MonteCarlo{Runs: 100, Seed: 7}
+	seeds := mc.Seeds()
+	if len(seeds) != mc.Runs {
+		t.Fatalf("got %d seeds, want %d", len(seeds), mc.Runs)
+	}
+	if again := mc.Seeds(); !reflect.DeepEqual(seeds, again) {
+		t.Error("the same seed gave different seeds")
+	}
+	distinct := map[int64]bool{}
+	for _, seed := range seeds {
+		distinct[seed] = true
+	}
+	if len(distinct) != mc.Runs {
+		t.Errorf("got %d distinct seeds for %d runs", len(distinct), mc.Runs)
+	}
+	mc.Seed = 8
+	if other := mc.Seeds(); reflect.DeepEqual(seeds, other) {
+		t.Error("a different seed gave the same seeds")
+	}
+}
+
+func TestSummarizeMonteCarlo(t *testing.T) {
+	summaries := []DrawSummary{}
+	for i := int64(1); i <= 10; i++ {
+		s := DrawSummary{FinalBalance: 1000 * (11 - i), RealFinalBalance: 500 * i}
+		if i > 7 {
+			s.ShortfallYears, s.TotalShortfall = 1, 100*i
+		}
+		summaries = append(summaries, s)
+	}
+	got := SummarizeMonteCarlo(summaries)
+	want := MonteCarloSummary{
+		Runs:                 10,
+		RunsWithoutShortfall: 7,
+		SuccessPct:           70,
+		FinalBalance:         Percentiles{P10: 1000, P50: 5000, P90: 9000},
+		RealFinalBalance:     Percentiles{P10: 500, P50: 2500, P90: 4500},
+		TotalShortfall:       Percentiles{P10: 0, P50: 0, P90: 900},
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := SummarizeMonteCarlo(nil); got != (MonteCarloSummary{}) {
+		t.Errorf("no runs: got %+v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package drawdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMonthlyStatePensionInstalments(t *testing.T) {
+	sp := NewStatePension("State Pension", 12010, 0, 1)
+	sp.openYear()
+	total := int64(0)
+	for period := 1; period <= 12; period++ {
+		sp.StartPeriod(1, period, 12)
+		got := totalSourceAmount(sp.Withdraw(100000))
+		if got != 1000 && got != 1001 {
+			t.Errorf("month %d: paid %d, want a twelfth of 12,010", period, got)
+		}
+		total += got
+	}
+	if total != 12010 {
+		t.Errorf("paid %d in the year, want 12010", total)
+	}
+	if f := sp.Flows(); f.Deposits != 12010 || f.Withdrawals != 12010 || sp.Balance() != 0 {
+		t.Errorf("flows %+v and balance %d, want the year's pension deposited once and withdrawn", f, sp.Balance())
+	}
+}
+
+// monthlyTestResult runs a scenario, with no growth or inflation, which draws on a GIA up to 3,000 a year,
+// of which half may be drawn up to 1,000, then on a taxable pension and then on savings.
+func monthlyTestResult(t *testing.T, monthly bool) DrawResult {
+	t.Helper()
+	s, err := DefineWith(func(b *Builder) {
+		r := b.Rates()
+		incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+		gia := b.AddSource(NewInvestmentAccount("GIA", 100000, &r.InvestmentGrowthRate))
+		pension := b.AddSource(NewPensionAccount("Pension", 100000, &r.InvestmentGrowthRate), incomeTax)
+		savings := b.AddSource(NewSavingsAccount("Savings", 100000, &r.SavingsGrowthRate))
+		giaAllowance := int64(3000)
+		b.DrawFrom(Seq(&giaAllowance, Upto(gia, 1000), gia), Upto(pension, 20000), savings).PayTaxFrom(savings)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	iterate := s.Iterate
+	if monthly {
+		iterate = s.IterateMonthly
+	}
+	result, err := iterate(2, 36000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestMonthlyDrawLimitsApplyToTheYear(t *testing.T) {
+	result := monthlyTestResult(t, true)
+	want := map[string][]int64{
+		"GIA":     {3000, 3000},
+		"Pension": {20000, 20000},
+		// The rest of the need, and in year 2 the tax of 1,486 raised on the pension in year 1.
+		"Savings": {13000, 14486},
+	}
+	got := map[string][]int64{}
+	for _, tr := range result.History {
+		got[tr.Source] = append(got[tr.Source], tr.Amount)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("withdrawals %v, want %v", got, want)
+	}
+}
+
+func TestMonthlyTaxIsReconciledEachTaxYear(t *testing.T) {
+	monthly, yearly := monthlyTestResult(t, true), monthlyTestResult(t, false)
+	for i, tr := range monthly.History {
+		if tr.TaxRaised != yearly.History[i].TaxRaised {
+			t.Errorf("year %d %s: tax raised %d monthly, %d yearly", tr.Year, tr.Source, tr.TaxRaised, yearly.History[i].TaxRaised)
+		}
+	}
+	taxEntries := map[int]int{}
+	for _, e := range monthly.Ledger {
+		if e.Kind == TaxEntry {
+			taxEntries[e.Year]++
+			// 20,000 drawn over the year, less the personal allowance of 12,570, at 20%.
+			if e.Source != "Pension" || e.Amount != 1486 {
+				t.Errorf("year %d: tax of %d raised on %s, want 1486 on the pension", e.Year, e.Amount, e.Source)
+			}
+		}
+	}
+	if !reflect.DeepEqual(taxEntries, map[int]int{1: 1, 2: 1}) {
+		t.Errorf("tax assessed %v times by year, want once in each tax year", taxEntries)
+	}
+}
+
+func TestMonthlyGrowthStartsInTheFirstMonth(t *testing.T) {
+	rate := 12.0
+	savings := NewSavingsAccount("Savings", 120000, &rate)
+	savings.openYear()
+	savings.StartPeriod(1, 1, 12)
+	// 12% a year is a little under 1% a month.
+	if g := savings.Flows().Growth; g < 1130 || g > 1140 {
+		t.Errorf("growth of %d in the first month, want about 1,134", g)
+	}
+	for period := 2; period <= 12; period++ {
+		savings.StartPeriod(1, period, 12)
+	}
+	if savings.Balance() < 134390 || savings.Balance() > 134410 {
+		t.Errorf("balance %d after a year, want 120,000 grown by 12%%", savings.Balance())
+	}
+}
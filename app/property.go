@@ -0,0 +1,95 @@
+package drawdown
+
+import (
+	"fmt"
+)
+
+// FinanceCostCreditRate is the rate of the tax credit given on residential mortgage interest
+// in place of deducting it from rental profits. (The basic rate, 20%).
+const FinanceCostCreditRate = 20.0
+
+// RentalTerms describe a buy-to-let property.
+// Percentages are expressed as, for example, 2.0 for 2%.
+type RentalTerms struct {
+	Year1AnnualRent        int64
+	RentAnnualPctIncrease  float64
+	LettingCostsPct        float64 // Letting fees, repairs, insurance etc. as a percentage of the rent.
+	MortgageBalance        int64   // The outstanding interest-only mortgage, repaid on sale.
+	MortgageRatePct        float64 // The annual mortgage interest rate.
+	PurchasePrice          int64   // The base cost used to calculate the gain on sale.
+	Year1Value             int64
+	ValueAnnualPctIncrease float64
+	SaleYear               int         // The year in which the property is sold (0 if it is never sold).
+	SaleCostsPct           float64     // Selling costs as a percentage of the sale price.
+	SaleTaxAccount         *TaxAccount // Capital gains tax account using residential property rates.
+	SaleProceedsTo         *Source     // The source into which the proceeds of sale are deposited.
+}
+
+// NewRentalProperty creates a buy-to-let property source.
+// Each year the balance is set to the rent less letting costs and mortgage interest.
+// Rental profit is taxed in the tax account the source is assigned to, with mortgage interest
+// attracting a basic-rate tax credit rather than being deducted from the profit.
+// A loss in any year is not carried forward.
+// At the start of the sale year the property is sold, the mortgage repaid, the remaining proceeds
+// deposited into t.SaleProceedsTo, and capital gains tax raised on the gain over the purchase price.
+// No rent is received in or after the sale year.
+func NewRentalProperty(name string, t RentalTerms) *Source {
+	is := &Source{
+		Name:              name,
+		hasPlatformCharge: false,
+		taxable:           true,
+		paysIncome:        true,
+		saleTax:           t.SaleTaxAccount,
+	}
+	rent := t.Year1AnnualRent
+	value := t.Year1Value
+	mortgage := t.MortgageBalance
+	sold := false
+	var profit, interest int64 // This year's rental profit (before interest) and mortgage interest.
+	is.startYear = func(year int) {
+		if year > 1 {
+			rent = int64(float64(rent) * (1 + t.RentAnnualPctIncrease/100))
+			value = int64(float64(value) * (1 + t.ValueAnnualPctIncrease/100))
+		}
+		profit, interest = 0, 0
+		is.propertyValue, is.propertyDebt = 0, 0
+		if sold {
+			is.setBalance(0)
+			return
+		}
+		if t.SaleYear > 0 && year >= t.SaleYear {
+			sold = true
+			is.setBalance(0)
+			costs := int64(float64(value) * t.SaleCostsPct / 100)
+			proceeds := max(0, value-costs-mortgage)
+			mortgage = 0
+			if t.SaleProceedsTo != nil {
+				t.SaleProceedsTo.Deposit(proceeds)
+				t.SaleProceedsTo.record(DepositEntry, proceeds, "proceeds of the sale of "+is.Name)
+			}
+			gain := max(0, value-costs-t.PurchasePrice)
+			if t.SaleTaxAccount != nil {
+				is.raiseTax(t.SaleTaxAccount.TaxOn(gain))
+			}
+			return
+		}
+		is.propertyValue, is.propertyDebt = value, mortgage
+		profit = rent - int64(float64(rent)*t.LettingCostsPct/100)
+		interest = int64(float64(mortgage) * t.MortgageRatePct / 100)
+		is.receiveIncome(max(0, profit-interest), fmt.Sprintf("rent of %d less letting costs and mortgage interest of %d", rent, interest))
+	}
+	// Rent is received in full whether or not it is needed.
+	is.makeWithdrawal = func(amount int64) []SourceAmount {
+		return is.reduceBalance(is.balance)
+	}
+	is.taxOn = func(ta *TaxAccount, amount int64) int64 {
+		if ta == nil || profit <= 0 {
+			return 0
+		}
+		tax := ta.TaxOn(profit)
+		credit := int64(float64(interest) * FinanceCostCreditRate / 100)
+		return max(0, tax-credit)
+	}
+	is.payInInstalments()
+	return is
+}
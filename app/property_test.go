@@ -0,0 +1,134 @@
+package drawdown
+
+import "testing"
+
+func rentalTerms(cash *Source, cgt *TaxAccount) RentalTerms {
+	return RentalTerms{
+		Year1AnnualRent:        12000,
+		RentAnnualPctIncrease:  3,
+		LettingCostsPct:        10,
+		MortgageBalance:        100000,
+		MortgageRatePct:        5,
+		PurchasePrice:          150000,
+		Year1Value:             200000,
+		ValueAnnualPctIncrease: 5,
+		SaleYear:               3,
+		SaleCostsPct:           2,
+		SaleTaxAccount:         cgt,
+		SaleProceedsTo:         cash,
+	}
+}
+
+func TestRentalIncomeAndFinanceCostCredit(t *testing.T) {
+	tests := []struct {
+		name        string
+		otherIncome int64 // Income already taxed in the account, such as a pension.
+		year        int
+		wantIncome  int64
+		wantTax     int64
+	}{
+		// Rent of 12,000 less 10% letting costs is a profit of 10,800, less mortgage interest of 5,000.
+		{"within the allowance", 0, 1, 5800, 0},
+		// 10,800 at 20% is 2,160, less a credit of 20% of the 5,000 interest.
+		{"basic rate", 20000, 1, 5800, 1160},
+		// 10,800 at 40% is 4,320, but the credit is still only at the basic rate.
+		{"higher rate", 60000, 1, 5800, 3320},
+		// Rent of 12,360 less 10% is 11,124; 11,124 at 20% is 2,224, less the credit of 1,000.
+		{"second year", 20000, 2, 6124, 1224},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cash := NewSavingsAccount("Cash", 0, new(float64))
+			cgt := NewTaxAccount("CGT", NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(HighUpperBound, 24)}))
+			flat := NewRentalProperty("Flat", rentalTerms(cash, cgt))
+			ta := NewTaxAccount("Income Tax", incomeTaxRegime())
+			for year := 1; year <= tt.year; year++ {
+				flat.StartYear(year)
+			}
+			ta.TaxOn(tt.otherIncome)
+			if flat.Balance() != tt.wantIncome {
+				t.Errorf("income %d, want %d", flat.Balance(), tt.wantIncome)
+			}
+			if got := flat.TaxOn(ta, flat.Balance()); got != tt.wantTax {
+				t.Errorf("tax %d, want %d", got, tt.wantTax)
+			}
+		})
+	}
+}
+
+func TestRentalPropertySale(t *testing.T) {
+	cash := NewSavingsAccount("Cash", 0, new(float64))
+	cgt := NewTaxAccount("CGT", NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(HighUpperBound, 24)}))
+	flat := NewRentalProperty("Flat", rentalTerms(cash, cgt))
+	flat.StartYear(1)
+	flat.StartYear(2)
+	if tax := flat.TakeRaisedTax(); tax != 0 {
+		t.Fatalf("tax of %d raised before the sale", tax)
+	}
+
+	flat.StartYear(3)
+	// The value has grown 5% a year to 220,500. Selling costs are 2%, or 4,410.
+	// The proceeds after repaying the mortgage of 100,000 are 116,090.
+	if cash.Balance() != 116090 {
+		t.Errorf("proceeds %d, want 116090", cash.Balance())
+	}
+	// The gain over the purchase price is 66,090, of which 63,090 is taxed at 24%.
+	if tax := flat.TakeRaisedTax(); tax != 15141 {
+		t.Errorf("capital gains tax %d, want 15141", tax)
+	}
+	if flat.Balance() != 0 {
+		t.Errorf("rent of %d received in the year of sale", flat.Balance())
+	}
+
+	flat.StartYear(4)
+	if flat.Balance() != 0 || cash.Balance() != 116090 || flat.TakeRaisedTax() != 0 {
+		t.Error("the property was still let or sold again after the sale")
+	}
+}
+
+func TestRentalPropertyCountsAsCapital(t *testing.T) {
+	cash := NewSavingsAccount("Cash", 0, new(float64))
+	flat := NewRentalProperty("Flat", rentalTerms(cash, nil))
+	s := &DrawScenario{Sources: []*Source{cash, flat}}
+	// The value less the mortgage of 100,000, and after the sale the proceeds in the cash account.
+	for year, want := range []int64{100000, 110000, 116090} {
+		cash.StartYear(year + 1)
+		flat.StartYear(year + 1)
+		if got := s.capital(false); got != want {
+			t.Errorf("year %d: capital %d, want %d", year+1, got, want)
+		}
+	}
+}
+
+func TestNetWorthIncludesRentalProperty(t *testing.T) {
+	s, err := DefineWith(func(b *Builder) {
+		incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+		cash := b.AddSource(NewSavingsAccount("Cash", 10000, new(float64)))
+		flat := b.AddSource(NewRentalProperty("Flat", rentalTerms(cash, nil)), incomeTax)
+		b.DrawFrom(flat, cash).PayTaxFrom(cash)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.Iterate(3, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nws := result.History.NetWorth()
+	if len(nws) != 3 {
+		t.Fatalf("got %d years of net worth, want 3", len(nws))
+	}
+	// The flat is worth 200,000 and then 210,000 with a mortgage of 100,000 until it is sold in year 3.
+	for i, want := range []struct{ value, mortgage int64 }{{200000, 100000}, {210000, 100000}, {0, 0}} {
+		balances := int64(0)
+		for _, tr := range result.History {
+			if tr.Year == i+1 {
+				balances += tr.Balance
+			}
+		}
+		if nws[i].Assets != balances+want.value || nws[i].Liabilities != want.mortgage {
+			t.Errorf("year %d: got assets %d and liabilities %d, want %d and %d",
+				i+1, nws[i].Assets, nws[i].Liabilities, balances+want.value, want.mortgage)
+		}
+	}
+}
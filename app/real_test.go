@@ -0,0 +1,85 @@
+package drawdown
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTransactionReal(t *testing.T) {
+	tr := Transaction{
+		Year: 3, Source: "Savings", Kind: Liquid, PriceIndex: 1.21,
+		Amount: 12100, Tax: 1210, TaxRaised: 2420, Balance: 121000,
+		Flows:         Flows{OpeningBalance: 133100, Growth: 3630, Fees: 1210, Deposits: 242, Withdrawals: 14762},
+		PropertyValue: 242000, PropertyDebt: 121000,
+	}
+	want := Transaction{
+		Year: 3, Source: "Savings", Kind: Liquid, PriceIndex: 1,
+		Amount: 10000, Tax: 1000, TaxRaised: 2000, Balance: 100000,
+		Flows:         Flows{OpeningBalance: 110000, Growth: 3000, Fees: 1000, Deposits: 200, Withdrawals: 12200},
+		PropertyValue: 200000, PropertyDebt: 100000,
+	}
+	// Deflating may leave an amount a unit short, as it is truncated.
+	near := func(got, want int64) bool { return math.Abs(float64(got-want)) <= 1 }
+	got := tr.Real()
+	for _, a := range [][2]int64{
+		{got.Amount, want.Amount}, {got.Tax, want.Tax}, {got.TaxRaised, want.TaxRaised}, {got.Balance, want.Balance},
+		{got.OpeningBalance, want.OpeningBalance}, {got.Growth, want.Growth}, {got.Fees, want.Fees},
+		{got.Deposits, want.Deposits}, {got.Withdrawals, want.Withdrawals},
+		{got.PropertyValue, want.PropertyValue}, {got.PropertyDebt, want.PropertyDebt},
+	} {
+		if !near(a[0], a[1]) {
+			t.Errorf("got %+v, want %+v", got, want)
+			break
+		}
+	}
+	if got.PriceIndex != 1 {
+		t.Errorf("price index %v after deflating, want 1", got.PriceIndex)
+	}
+	if again := got.Real(); !reflect.DeepEqual(again, got) {
+		t.Error("deflating a second time changed the transaction")
+	}
+}
+
+func TestRealDeflatesByCumulativeInflation(t *testing.T) {
+	s := defineTestScenario(t)
+	s.Rates.AnnualInflationRate = 10
+	result, err := s.Iterate(5, 30000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deflated := result.Real()
+	finalBalance := int64(0)
+	for i, tr := range result.History {
+		want := math.Pow(1.1, float64(tr.Year-1))
+		if math.Abs(tr.PriceIndex-want) > 1e-9 {
+			t.Fatalf("year %d: price index %v, want %v", tr.Year, tr.PriceIndex, want)
+		}
+		r := deflated.History[i]
+		if r.Balance != int64(float64(tr.Balance)/want) || r.Amount != int64(float64(tr.Amount)/want) || r.PriceIndex != 1 {
+			t.Errorf("year %d %s: got %+v, want the amounts of %+v divided by %v", tr.Year, tr.Source, r, tr, want)
+		}
+		if tr.Year == 5 && tr.Kind == Liquid {
+			finalBalance += r.Balance
+		}
+	}
+	summary := result.Summary()
+	if summary.RealFinalBalance != finalBalance || summary.RealFinalBalance >= summary.FinalBalance {
+		t.Errorf("real final balance %d, want %d, below the final balance %d", summary.RealFinalBalance, finalBalance, summary.FinalBalance)
+	}
+	for i, e := range result.Ledger {
+		if want := int64(float64(e.Amount) / math.Pow(1.1, float64(e.Year-1))); deflated.Ledger[i].Amount != want {
+			t.Fatalf("ledger entry %+v deflated to %d, want %d", e, deflated.Ledger[i].Amount, want)
+		}
+	}
+
+	s = defineTestScenario(t)
+	s.Rates.AnnualInflationRate = 0
+	result, err = s.Iterate(5, 30000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary := result.Summary(); summary.RealFinalBalance != summary.FinalBalance {
+		t.Errorf("without inflation the real final balance %d differs from the final balance %d", summary.RealFinalBalance, summary.FinalBalance)
+	}
+}
@@ -1,6 +1,7 @@
 package drawdown
 
 import (
+	"fmt"
 	"math"
 	"strings"
 )
@@ -11,30 +12,134 @@ type SourceAmount struct {
 	Amount int64
 }
 
+// SourceKind distinguishes sources which can be drawn on from those which only count towards the estate.
+type SourceKind int
+
+const (
+	Liquid    SourceKind = iota // Can be drawn on to meet the need.
+	Illiquid                    // Counts towards the estate but is never drawn on, such as the main residence.
+	Credit                      // Can be drawn on but is borrowing rather than an asset, such as an equity release facility.
+	Liability                   // The balance is an amount owed, such as a mortgage or loan. It is never drawn on.
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case Liquid:
+		return "Liquid"
+	case Illiquid:
+		return "Illiquid"
+	case Credit:
+		return "Credit"
+	case Liability:
+		return "Liability"
+	}
+	return "Unknown"
+}
+
+// MarshalText encodes the kind by name, so that it reads as such in JSON.
+func (k SourceKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
 // Source represents something from which income can be drawn.
 // This might be a savings account, an investment account, or a pension, for example.
 type Source struct {
 	Name              string
-	balance           int64                             // The amount of money currently in the source.
-	year              int                               // The current year (origin one) - decisions might be based on this.
-	hasPlatformCharge bool                              // the balance counts towards the platform charge.
-	startYear         func(year int)                    // Called at the beginning of each year typically to set the opening balance (year origin is zero).
-	endYear           func(year int)                    // Called at the end of each year.
-	makeWithdrawal    func(amount int64) []SourceAmount // nil, else it returns the amount withdrawn from the source.
+	kind              SourceKind
+	balance           int64                                    // The amount of money currently in the source.
+	year              int                                      // The current year (origin one) - decisions might be based on this.
+	hasPlatformCharge bool                                     // the balance counts towards the platform charge.
+	startYear         func(year int)                           // Called at the beginning of each year typically to set the opening balance (year origin is zero).
+	startPeriod       func(year, period, periods int)          // nil, else called at the beginning of each period when a year is divided into periods.
+	endYear           func(year int)                           // Called at the end of each year.
+	makeWithdrawal    func(amount int64) []SourceAmount        // nil, else it returns the amount withdrawn from the source.
+	taxOn             func(ta *TaxAccount, amount int64) int64 // nil, else it returns the tax raised on a withdrawal of amount.
+	raisedTax         int64                                    // Tax raised by the source this year other than on withdrawals, such as on a sale.
+	moved             int64                                    // Money moved out of the source this year by an action, such as Transfer or PayOff.
+	repaymentDue      int64                                    // The scheduled repayment of a liability this year, which is added to the need.
+	ledger            *Ledger                                  // nil, else the ledger of the iteration in which the source is taking part.
+	flows             Flows                                    // Movements of money into and out of the source this year.
+	fees              *FeeSchedule                             // nil, else the fees charged instead of the platform charge rate.
+	taxable           bool                                     // Withdrawals are taxable, so the source needs a tax account.
+	wraps             []*Source                                // The sources drawn on by a combination such as Seq or Split.
+	splitPcts         []int64                                  // For a Split, the percentage drawn from each of wraps.
+	capDrawn          int64                                    // For a capped combination such as Seq or Upto, the amount drawn so far this year.
+	paysIncome        bool                                     // The balance is income for the year, such as a pension or earnings, rather than capital.
+	income            int64                                    // The income for the year assessed by a care means test.
+	propertyValue     int64                                    // The value of a property held by the source, which is not part of its balance.
+	propertyDebt      int64                                    // The mortgage secured on the property.
+	ni                *NIRegime                                // nil, else the National Insurance regime under which contributions are raised on earnings.
+	saleTax           *TaxAccount                              // nil, else the tax account in which the gain on the sale of a property is taxed.
+}
+
+// Flows are the movements of money into and out of a source during a year.
+// OpeningBalance + Growth - Fees + Deposits - Withdrawals is the closing balance.
+type Flows struct {
+	OpeningBalance int64
+	Growth         int64 // Growth, or for a liability the interest charged.
+	Fees           int64 // Platform charges.
+	Deposits       int64 // Money paid in, including income made available for the year.
+	Withdrawals    int64 // Money taken out, including transfers and repayments.
 }
 
 // setBalance sets the source's balance to a given value.
+// An increase in the balance is counted as a deposit and a decrease as a withdrawal.
 func (is *Source) setBalance(amount int64) {
 	if amount < 0 {
-		panic("Cannot set a negative balance")
+		panic(&InvalidScenario{Source: is.Name, Reason: fmt.Sprintf("cannot set a negative balance of %d", amount)})
+	}
+	if amount > is.balance {
+		is.flows.Deposits += amount - is.balance
+	} else {
+		is.flows.Withdrawals += is.balance - amount
 	}
 	is.balance = amount
 }
 
+// growTo sets the source's balance to a given value, counting the change as growth.
+func (is *Source) growTo(amount int64) {
+	if amount < 0 {
+		panic(&InvalidScenario{Source: is.Name, Reason: fmt.Sprintf("cannot grow to a negative balance of %d", amount)})
+	}
+	is.flows.Growth += amount - is.balance
+	is.balance = amount
+}
+
+// chargeFee deducts a fee, up to the whole balance, from the source and returns the amount deducted.
+func (is *Source) chargeFee(amount int64) int64 {
+	fee := min(amount, is.balance)
+	is.flows.Fees += fee
+	is.balance -= fee
+	return fee
+}
+
+// openYear starts a new year of flows with the current balance as the opening balance.
+func (is *Source) openYear() {
+	is.flows = Flows{OpeningBalance: is.balance}
+	is.income = 0
+}
+
+// receiveIncome sets the balance of a source of income to the income for the year,
+// which a care means test assesses as income.
+func (is *Source) receiveIncome(amount int64, note string) {
+	is.setBalance(amount)
+	is.income = amount
+	is.record(IncomeEntry, amount, note)
+}
+
+// Flows returns the movements of money into and out of the source so far this year.
+func (is *Source) Flows() Flows {
+	return is.flows
+}
+
 func (is *Source) Balance() int64 {
 	return is.balance
 }
 
+func (is *Source) Kind() SourceKind {
+	return is.kind
+}
+
 func (is *Source) PlatformChargeBalance() int64 {
 	if is.hasPlatformCharge {
 		return is.balance
@@ -45,7 +150,7 @@ func (is *Source) PlatformChargeBalance() int64 {
 func (is *Source) reduceBalance(amount int64) []SourceAmount {
 	if is.balance < amount {
 		amount = is.balance
-		is.balance = 0
+		is.setBalance(0)
 	} else {
 		is.setBalance(is.balance - amount)
 	}
@@ -64,7 +169,7 @@ func (is *Source) increaseBalance(amount int64) {
 func (is *Source) Withdraw(amount int64) []SourceAmount {
 	if amount < 0 {
 		//fmt.Println("amount", amount, "account", is.Name)
-		panic("Cannot withdraw negative amount")
+		panic(&InvalidScenario{Source: is.Name, Reason: fmt.Sprintf("cannot withdraw a negative amount of %d", amount)})
 	}
 	if is.makeWithdrawal != nil {
 		return is.makeWithdrawal(amount)
@@ -75,7 +180,7 @@ func (is *Source) Withdraw(amount int64) []SourceAmount {
 func (is *Source) Deposit(amount int64) {
 	if amount < 0 {
 		//fmt.Println("deposit", amount, "account", is.Name)
-		panic("Cannot deposit negative amount")
+		panic(&InvalidScenario{Source: is.Name, Reason: fmt.Sprintf("cannot deposit a negative amount of %d", amount)})
 	}
 	is.increaseBalance(amount)
 }
@@ -90,6 +195,55 @@ func (is *Source) StartYear(year int) {
 	is.startYear(year)
 }
 
+// StartPeriod is called at the beginning of each of the periods into which a year is divided.
+// The period origin is one.
+// Sources which do not distinguish periods start the year at the beginning of the first period.
+func (is *Source) StartPeriod(year int, period int, periods int) {
+	if periods == 1 || is.startPeriod == nil {
+		if period == 1 {
+			is.StartYear(year)
+		}
+		return
+	}
+	is.year = year
+	is.startPeriod(year, period, periods)
+}
+
+// payInInstalments makes an income source, whose startYear sets the balance to the amount paid in the year,
+// pay that amount in equal instalments when the year is divided into periods.
+// The income for the year is counted once, as a deposit, when the year starts.
+// The instalments then release it into the balance without counting as further flows,
+// so the flows reconcile with the closing balance at the end of the year.
+func (is *Source) payInInstalments() {
+	var annual int64
+	is.startPeriod = func(year, period, periods int) {
+		if period == 1 {
+			is.startYear(year) // Records the income for the year.
+			annual = is.balance
+			is.balance = 0
+		}
+		is.balance += periodShare(annual, period, periods)
+	}
+}
+
+// growInPeriods makes a source grow by annualPctIncrease compounded over the periods into which a year is divided,
+// starting in the first period of the first year, as money held for a month earns that month's growth.
+func (is *Source) growInPeriods(annualPctIncrease *float64) {
+	is.startPeriod = func(year, period, periods int) {
+		is.grow(math.Pow(1+*annualPctIncrease/100, 1/float64(periods))-1, *annualPctIncrease)
+	}
+}
+
+// grow scales the balance up by the given rate (for example, 0.02 for 2%)
+// and records the growth in the ledger against the equivalent annual percentage.
+func (is *Source) grow(rate float64, annualPct float64) {
+	before := is.balance
+	is.growTo(int64((1 + rate) * float64(is.balance)))
+	if is.balance != before {
+		is.record(GrowthEntry, is.balance-before, fmt.Sprintf("growth at %.2f%% a year", annualPct))
+	}
+}
+
 // EndYear is called at the end of each year.
 // The year origin is one.
 func (is *Source) EndYear(year int) {
@@ -99,6 +253,42 @@ func (is *Source) EndYear(year int) {
 	is.endYear(year)
 }
 
+// TaxOn returns the tax raised by withdrawing the given amount from the source this year
+// and records it in the tax account ta, which is nil if the source is not taxable.
+func (is *Source) TaxOn(ta *TaxAccount, amount int64) int64 {
+	if is.taxOn != nil {
+		return is.taxOn(ta, amount)
+	}
+	if ta == nil {
+		return 0
+	}
+	return ta.TaxOn(amount)
+}
+
+// RepaymentDue returns the scheduled repayment due on a liability this year.
+func (is *Source) RepaymentDue() int64 {
+	return is.repaymentDue
+}
+
+// raiseTax records tax raised by the source other than on a withdrawal.
+func (is *Source) raiseTax(amount int64) {
+	is.raisedTax += amount
+}
+
+// TakeRaisedTax returns the tax raised by the source other than on withdrawals since it was last called.
+func (is *Source) TakeRaisedTax() int64 {
+	tax := is.raisedTax
+	is.raisedTax = 0
+	return tax
+}
+
+// takeMoved returns the money moved out of the source by actions since it was last called.
+func (is *Source) takeMoved() int64 {
+	moved := is.moved
+	is.moved = 0
+	return moved
+}
+
 // IsEmpty returns true if the source's balance is 0.
 func (is *Source) IsEmpty() bool {
 	return is.balance == 0
@@ -112,6 +302,8 @@ func NewStatePension(name string, year1AnnualAmount int64, annualPctIncrease flo
 	is := &Source{
 		Name:              name,
 		hasPlatformCharge: false,
+		taxable:           true,
+		paysIncome:        true,
 	}
 	// Set a new opening balance each year which is scaled up by the annual percentage increase.
 	is.startYear = func(year int) {
@@ -122,7 +314,7 @@ func NewStatePension(name string, year1AnnualAmount int64, annualPctIncrease flo
 		if is.year < startingYear {
 			is.setBalance(0)
 		} else {
-			is.setBalance(newBalance)
+			is.receiveIncome(newBalance, "state pension for the year")
 		}
 	}
 	is.makeWithdrawal = func(amount int64) []SourceAmount {
@@ -133,6 +325,54 @@ func NewStatePension(name string, year1AnnualAmount int64, annualPctIncrease flo
 		*/
 		return is.reduceBalance(is.balance)
 	}
+	is.payInInstalments()
+	return is
+}
+
+// NewEarnings creates a source of employment or self-employment income for phased retirement.
+// Year1AnnualAmount is the gross amount earned in year 1.
+// The amount grows each year by the inflation rate plus a real growth rate. (For example, 1.0 for 1% above inflation).
+// Earnings are paid from startingYear up to and including endingYear.
+// National Insurance contributions are raised on the earnings under the given regime
+// in addition to any income tax raised by the tax account the source is assigned to.
+func NewEarnings(name string, year1AnnualAmount int64, inflationPct *float64, realGrowthPct float64, startingYear int, endingYear int, ni *NIRegime) *Source {
+	is := &Source{
+		Name:              name,
+		hasPlatformCharge: false,
+		taxable:           true,
+		paysIncome:        true,
+		ni:                ni,
+	}
+	annualAmount := year1AnnualAmount
+	// Set a new opening balance each year which is scaled up by inflation and real growth.
+	// Earnings are disregarded by a care means test, so they are not received as assessed income.
+	is.startYear = func(year int) {
+		if year > 1 {
+			growth := (1 + *inflationPct/100) * (1 + realGrowthPct/100)
+			annualAmount = int64(growth * float64(annualAmount))
+		}
+		if year < startingYear || year > endingYear {
+			is.setBalance(0)
+		} else {
+			is.setBalance(annualAmount)
+			is.record(IncomeEntry, annualAmount, "earnings for the year")
+		}
+	}
+	// Earnings are received in full whether or not they are needed.
+	is.makeWithdrawal = func(amount int64) []SourceAmount {
+		return is.reduceBalance(is.balance)
+	}
+	is.taxOn = func(ta *TaxAccount, amount int64) int64 {
+		tax := int64(0)
+		if ta != nil {
+			tax = ta.TaxOn(amount)
+		}
+		if ni != nil {
+			tax += ni.ContributionsDue(amount)
+		}
+		return tax
+	}
+	is.payInInstalments()
 	return is
 }
 
@@ -150,9 +390,9 @@ func NewSavingsAccount(name string, initialBalance int64, annualPctIncrease *flo
 		if year == 1 {
 			return
 		}
-		increasePct := *annualPctIncrease / 100
-		is.setBalance(int64((1 + increasePct) * float64(is.balance)))
+		is.grow(*annualPctIncrease/100, *annualPctIncrease)
 	}
+	is.growInPeriods(annualPctIncrease)
 	return is
 }
 
@@ -173,28 +413,56 @@ func NewInvestmentAccount(name string, initialBalance int64, annualPctIncrease *
 		}
 
 		// Scale the balance up each year (apart for the first) by the annual percentage increase.
-		increasePct := *annualPctIncrease / 100
-		is.setBalance(int64((1 + increasePct) * float64(is.balance)))
+		is.grow(*annualPctIncrease/100, *annualPctIncrease)
 	}
+	is.growInPeriods(annualPctIncrease)
 	return is
 }
 
+// NewPensionAccount creates a pension invested in funds or shares, whose withdrawals are taxable income.
+// It is an investment account which must be given an income tax account.
+func NewPensionAccount(name string, initialBalance int64, annualPctIncrease *float64) *Source {
+	return NewInvestmentAccount(name, initialBalance, annualPctIncrease).Taxable()
+}
+
+// Taxable marks the source as one whose withdrawals are taxable, so that Validate reports it if it has no tax account.
+func (is *Source) Taxable() *Source {
+	is.taxable = true
+	return is
+}
+
+// startDrawing resets the amounts drawn this year by the source and by any capped combinations it wraps,
+// so that the caps of Seq and Upto apply to each year however many times the source is drawn on in it.
+func (is *Source) startDrawing() {
+	is.capDrawn = 0
+	for _, w := range is.wraps {
+		w.startDrawing()
+	}
+}
+
+// Upto returns a Source which, on withdrawal, draws from is up to upto a year, whatever the amount requested.
 func Upto(is *Source, upto int64) *Source {
 	nis := &Source{
-		Name: is.Name,
+		Name:  is.Name,
+		wraps: []*Source{is},
 	}
 	nis.makeWithdrawal = func(amount int64) []SourceAmount {
-		return is.reduceBalance(min(is.balance, upto))
+		got := is.reduceBalance(min(is.balance, max(0, upto-nis.capDrawn)))
+		nis.capDrawn += totalSourceAmount(got)
+		return got
 	}
 	return nis
 }
 
+// Seq returns a Source which, on withdrawal, draws from each of iss in turn until the amount requested is met,
+// drawing no more than upto in a year.
 func Seq(upto *int64, iss ...*Source) *Source {
 	is := &Source{
-		Name: "Seq " + strings.Join(incomeSourceNames(iss), " + "),
+		Name:  "Seq " + strings.Join(incomeSourceNames(iss), " + "),
+		wraps: iss,
 	}
 	is.makeWithdrawal = func(amount int64) []SourceAmount {
-		need := min(amount, *upto)
+		need := min(amount, max(0, *upto-is.capDrawn))
 		sources := make([]SourceAmount, 0)
 		for _, is := range iss {
 			got := is.Withdraw(need)
@@ -204,27 +472,57 @@ func Seq(upto *int64, iss ...*Source) *Source {
 				break
 			}
 		}
+		is.capDrawn += totalSourceAmount(sources)
 		return sources
 	}
 	return is
 }
 
 // Transfer can be used as an action to move money between sources.
+// The money moved is withdrawn from its sources, so a taxable source is taxed on it.
 func Transfer(upto *int64, to *Source, from ...*Source) *Source {
 	sources := Seq(upto, from...).Withdraw(*upto)
 	got := totalSourceAmount(sources)
 	to.Deposit(got)
+	for _, sa := range sources {
+		if sa.Amount > 0 {
+			sa.Source.moved += sa.Amount
+			sa.Source.record(TransferEntry, -sa.Amount, "transferred to "+to.Name)
+			to.record(TransferEntry, sa.Amount, "transferred from "+sa.Source.Name)
+		}
+	}
 	//fmt.Println("Transfer", got, "to", to.Name, "from", strings.Join(incomeSourceNames(from), " + "))
 	return &Source{
 		Name: "Transfer to " + to.Name + " from " + strings.Join(incomeSourceNames(from), " + "),
 	}
 }
 
+// PayOff can be used as an action to repay a liability early, in whole or in part, from the given sources.
+// This year's scheduled repayment is still added to the need.
+// The money repaid is withdrawn from its sources, so a taxable source is taxed on it.
+// It returns the amount repaid.
+func PayOff(liability *Source, from ...*Source) int64 {
+	outstanding := liability.balance
+	sources := Seq(&outstanding, from...).Withdraw(outstanding)
+	got := totalSourceAmount(sources)
+	liability.setBalance(liability.balance - got)
+	for _, sa := range sources {
+		if sa.Amount > 0 {
+			sa.Source.moved += sa.Amount
+			sa.Source.record(TransferEntry, -sa.Amount, "paid off "+liability.Name)
+			liability.record(RepaymentEntry, sa.Amount, "early repayment from "+sa.Source.Name)
+		}
+	}
+	return got
+}
+
 // Return a new Source which, on withdrawal, will draw from source1 and source2 in the given percentages.
 // The percentages are expressed as, for example, 2.0 for 2%.
 func Split(is1 *Source, is2 *Source, pct1 int64, pct2 int64) *Source {
 	is := &Source{
-		Name: is1.Name + " + " + is2.Name,
+		Name:      is1.Name + " + " + is2.Name,
+		wraps:     []*Source{is1, is2},
+		splitPcts: []int64{pct1, pct2},
 	}
 	is.makeWithdrawal = func(amount int64) []SourceAmount {
 		a1 := is1.Withdraw(amount * pct1 / 100)
@@ -2,10 +2,16 @@ package drawdown
 
 // Summary is an aggregation of a set of transactions.
 type DrawSummary struct {
-	TotalWithdrawn int64
-	TotalTaxPaid   int64
-	FinalBalance   int64
-	FinalYear      int
+	TotalWithdrawn     int64
+	TotalTaxPaid       int64
+	TotalTaxRaised     int64 // The tax raised on withdrawals and disposals, which is paid the following year.
+	FinalBalance       int64 // The balance of the liquid sources at the end of the final year.
+	RealFinalBalance   int64 // The final balance in today's (year 1) money.
+	FinalEstate        int64 // The net worth, including illiquid sources such as the home, at the end of the final year.
+	FinalYear          int
+	ShortfallYears     int   // The number of years in which the need could not be met.
+	FirstShortfallYear int   // The first year in which the need could not be met, or 0.
+	TotalShortfall     int64 // The total need which could not be met.
 }
 
 // Summary returns a summary of the given DrawHistory transactions.
@@ -13,14 +19,70 @@ type DrawSummary struct {
 func (h DrawHistory) Summary() DrawSummary {
 	s := DrawSummary{}
 	balanceByYear := map[int]int64{}
+	realBalanceByYear := map[int]int64{}
 	for _, t := range h {
 		s.TotalWithdrawn += t.Amount
 		s.TotalTaxPaid += t.Tax
-		balanceByYear[t.Year] += t.Balance
+		s.TotalTaxRaised += t.TaxRaised
+		if t.Kind == Liquid {
+			balanceByYear[t.Year] += t.Balance
+			realBalanceByYear[t.Year] += t.Real().Balance
+		}
 		if t.Year > s.FinalYear {
 			s.FinalYear = t.Year
 		}
 	}
 	s.FinalBalance = balanceByYear[s.FinalYear]
+	s.RealFinalBalance = realBalanceByYear[s.FinalYear]
+	if nws := h.NetWorth(); len(nws) > 0 {
+		s.FinalEstate = nws[len(nws)-1].Value()
+	}
 	return s
 }
+
+// Summary returns a summary of the result's transactions and shortfalls.
+func (r DrawResult) Summary() DrawSummary {
+	s := r.History.Summary()
+	for _, sf := range r.Shortfalls() {
+		if s.ShortfallYears == 0 {
+			s.FirstShortfallYear = sf.Year
+		}
+		s.ShortfallYears++
+		s.TotalShortfall += sf.Amount
+	}
+	return s
+}
+
+// NetWorth is the value of the assets less the liabilities at the end of a year.
+type NetWorth struct {
+	Year        int
+	TaxYear     string
+	Ages        []int
+	Assets      int64 // The balance of the liquid and illiquid sources and the value of any rental property.
+	Liabilities int64 // The balance of the liability sources and the mortgages on any rental property.
+}
+
+func (nw NetWorth) Value() int64 {
+	return nw.Assets - nw.Liabilities
+}
+
+// NetWorth returns the net worth at the end of each year in the DrawHistory.
+// NetWorth relies on DrawHistory being sorted by increasing year.
+func (h DrawHistory) NetWorth() []NetWorth {
+	nws := []NetWorth{}
+	for _, t := range h {
+		if len(nws) == 0 || nws[len(nws)-1].Year != t.Year {
+			nws = append(nws, NetWorth{Year: t.Year, TaxYear: t.TaxYear, Ages: t.Ages})
+		}
+		nw := &nws[len(nws)-1]
+		switch t.Kind {
+		case Liquid, Illiquid:
+			nw.Assets += t.Balance
+		case Liability:
+			nw.Liabilities += t.Balance
+		}
+		nw.Assets += t.PropertyValue
+		nw.Liabilities += t.PropertyDebt
+	}
+	return nws
+}
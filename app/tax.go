@@ -1,6 +1,7 @@
 package drawdown
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -42,6 +43,12 @@ func (ta *TaxAccount) TaxDue(amount int64) int64 {
 	return ta.regime.TaxDue(amount, ta.taxedamount)
 }
 
+// Bands returns how the given amount falls across the bands of the tax regime,
+// given what has already been taxed in the tax account.
+func (ta *TaxAccount) Bands(amount int64) []BandAmount {
+	return ta.regime.Bands(amount, ta.taxedamount)
+}
+
 // TaxRegime describes the rates of tax that are charged on increasing amounts.
 // Commonly the first rateBound in the slice might represents a tax-free allowance.
 type TaxRegime struct {
@@ -70,6 +77,32 @@ func (tr TaxRegime) TaxFreeAllowance() int64 {
 	return tr.Rates[0].upper
 }
 
+// NIClass identifies the class of National Insurance contributions raised by an NIRegime.
+type NIClass int
+
+const (
+	NIClass1 NIClass = 1 // Employee (primary) Class 1 contributions on employment earnings.
+	NIClass4 NIClass = 4 // Class 4 contributions on self-employed profits.
+)
+
+// NIRegime describes the rates of National Insurance contributions charged on increasing amounts of earnings.
+// Unlike income tax, contributions are assessed on each source of earnings alone:
+// pension and other income does not use up the bands.
+// Builder.NIWith adds it to a scenario's tax regimes so that its thresholds are scaled each year.
+type NIRegime struct {
+	Class NIClass
+	TaxRegime
+}
+
+func NewNIRegime(class NIClass, rates []RateBound) NIRegime {
+	return NIRegime{Class: class, TaxRegime: NewTaxRegime(rates)}
+}
+
+// ContributionsDue returns the National Insurance contributions due on the given annual earnings.
+func (nr NIRegime) ContributionsDue(earnings int64) int64 {
+	return nr.taxDue(earnings)
+}
+
 // RateBound contains a rate and an upper bound on the amount for which the rate applies.
 // A slice of RateBound is used to describe a tax regime.
 // In such a slice, subsequent upper values must be strictly increasing.
@@ -91,6 +124,32 @@ func (tr TaxRegime) TaxDue(a int64, already int64) int64 {
 	return tr.taxDue(a+already) - tr.taxDue(already)
 }
 
+// BandAmount is the part of an amount which falls within one band of a tax regime.
+type BandAmount struct {
+	Rate   float64
+	Amount int64
+}
+
+// Bands returns how the additional amount 'a', over and above the amount 'already', falls across the bands of the regime.
+func (tr TaxRegime) Bands(a int64, already int64) []BandAmount {
+	bands := []BandAmount{}
+	lastUpper := int64(0)
+	for _, rb := range tr.Rates {
+		if a == 0 {
+			break
+		}
+		lower := max(lastUpper, already)
+		lastUpper = rb.upper
+		if lower >= rb.upper {
+			continue
+		}
+		inBand := min(a, rb.upper-lower)
+		a -= inBand
+		bands = append(bands, BandAmount{Rate: rb.rate, Amount: inBand})
+	}
+	return bands
+}
+
 // taxDue returns the amount of tax due on an amount in the given tax regime.
 func (tr TaxRegime) taxDue(a int64) int64 {
 	remaining := a
@@ -106,7 +165,7 @@ func (tr TaxRegime) taxDue(a int64) int64 {
 		lastUpper = rb.upper
 	}
 	if remaining > 0 {
-		panic("tax regime failed - tax remaining")
+		panic(&InvalidScenario{Reason: fmt.Sprintf("tax regime does not cover an amount of %d", a)})
 	}
 	return due
 }
@@ -0,0 +1,124 @@
+package drawdown
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func incomeTaxRegime() TaxRegime {
+	return NewTaxRegime([]RateBound{
+		NewRateBound(12570, 0),
+		NewRateBound(50270, 20),
+		NewRateBound(125140, 40),
+		NewRateBound(HighUpperBound, 45),
+	})
+}
+
+func class1NIRegime() NIRegime {
+	return NewNIRegime(NIClass1, []RateBound{
+		NewRateBound(12570, 0),
+		NewRateBound(50270, 8),
+		NewRateBound(HighUpperBound, 2),
+	})
+}
+
+func TestTaxRegimeTaxDue(t *testing.T) {
+	tests := []struct {
+		amount, already, want int64
+	}{
+		{0, 0, 0},
+		{12570, 0, 0},
+		{12575, 0, 1},             // 20% of 5.
+		{30000, 0, 3486},          // 17,430 at 20%.
+		{60000, 0, 11432},         // 37,700 at 20% and 9,730 at 40%.
+		{130000, 0, 39675},        // 37,700 at 20%, 74,870 at 40% and 4,860 at 45%.
+		{30000, 11000, 5686},      // 28,430 at 20% after 11,000 already taxed.
+		{10000, 45270, 3000},      // 5,000 at 20% and 5,000 at 40%.
+		{1000, 124640, 200 + 225}, // 500 at 40% and 500 at 45%.
+	}
+	tr := incomeTaxRegime()
+	for _, tt := range tests {
+		if got := tr.TaxDue(tt.amount, tt.already); got != tt.want {
+			t.Errorf("TaxDue(%d, %d) = %d, want %d", tt.amount, tt.already, got, tt.want)
+		}
+	}
+}
+
+func TestTaxRegimeBands(t *testing.T) {
+	tests := []struct {
+		amount, already int64
+		want            []BandAmount
+	}{
+		{0, 0, []BandAmount{}},
+		{20000, 0, []BandAmount{{0, 12570}, {20, 7430}}},
+		{10000, 45270, []BandAmount{{20, 5000}, {40, 5000}}},
+		{1000, 200000, []BandAmount{{45, 1000}}},
+	}
+	tr := incomeTaxRegime()
+	for _, tt := range tests {
+		if got := tr.Bands(tt.amount, tt.already); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Bands(%d, %d) = %v, want %v", tt.amount, tt.already, got, tt.want)
+		}
+	}
+}
+
+func TestTaxRegimeNotCoveringAnAmountPanics(t *testing.T) {
+	tr := NewTaxRegime([]RateBound{NewRateBound(1000, 0), NewRateBound(2000, 20)})
+	defer func() {
+		var is *InvalidScenario
+		if err, _ := recover().(error); !errors.As(err, &is) {
+			t.Errorf("got %v, want an *InvalidScenario", err)
+		}
+	}()
+	tr.TaxDue(3000, 0)
+}
+
+func TestNIRegimeContributionsDue(t *testing.T) {
+	tests := []struct {
+		earnings, want int64
+	}{
+		{0, 0},
+		{12570, 0},
+		{30000, 1394},  // 17,430 at 8%, rounded down.
+		{50270, 3016},  // The whole main rate band.
+		{60000, 3210},  // 37,700 at 8% and 9,730 at 2%, rounded down.
+		{100000, 4010}, // 37,700 at 8% and 49,730 at 2%, rounded down.
+	}
+	ni := class1NIRegime()
+	for _, tt := range tests {
+		if got := ni.ContributionsDue(tt.earnings); got != tt.want {
+			t.Errorf("ContributionsDue(%d) = %d, want %d", tt.earnings, got, tt.want)
+		}
+	}
+}
+
+func TestEarningsRaiseIncomeTaxAndNI(t *testing.T) {
+	tests := []struct {
+		name            string
+		alreadyTaxed    int64 // Other income, such as a pension, already taxed in the account.
+		earnings        int64
+		want            int64
+		wantTaxedAmount int64
+	}{
+		{"earnings alone", 0, 30000, 3486 + 1394, 30000},
+		// Other income uses up the income tax allowance but not the National Insurance threshold.
+		{"after a pension", 11000, 30000, 5686 + 1394, 41000},
+		{"below the thresholds", 0, 12000, 0, 12000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inflation := 2.0
+			ni := class1NIRegime()
+			ta := NewTaxAccount("Income Tax", incomeTaxRegime())
+			ta.TaxOn(tt.alreadyTaxed)
+			earnings := NewEarnings("Earnings", tt.earnings, &inflation, 0, 1, 1, &ni)
+			if got := earnings.TaxOn(ta, tt.earnings); got != tt.want {
+				t.Errorf("TaxOn = %d, want %d", got, tt.want)
+			}
+			if ta.taxedamount != tt.wantTaxedAmount {
+				t.Errorf("the account has taxed %d, want %d", ta.taxedamount, tt.wantTaxedAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package drawdown
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Problems are all the ways in which a scenario is invalid, as found by Validate.
+type Problems []*InvalidScenario
+
+func (ps Problems) Error() string {
+	msgs := make([]string, len(ps))
+	for i, p := range ps {
+		msgs[i] = p.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (ps Problems) Unwrap() []error {
+	errs := make([]error, len(ps))
+	for i, p := range ps {
+		errs[i] = p
+	}
+	return errs
+}
+
+// Validate checks the scenario for mistakes which would otherwise go unnoticed or only show up
+// part way through an iteration. It returns nil, or Problems listing every mistake found.
+func (s *DrawScenario) Validate() error {
+	ps := Problems{}
+	problem := func(source *Source, format string, a ...any) {
+		name := ""
+		if source != nil {
+			name = source.Name
+		}
+		ps = append(ps, &InvalidScenario{Source: name, Reason: fmt.Sprintf(format, a...)})
+	}
+
+	sources := s.allSources()
+	for i, source := range sources {
+		if source == nil {
+			problem(nil, "source %d is nil", i+1)
+		} else if slices.Index(sources, source) < i {
+			problem(source, "listed more than once in Sources")
+		}
+	}
+
+	if len(s.DrawSequence) == 0 {
+		problem(nil, "the draw sequence is empty")
+	}
+	drawn := []*Source{}
+	for _, entry := range s.DrawSequence {
+		drawn = validateEntry(entry, drawn, problem)
+	}
+	for _, source := range drawn {
+		if !slices.Contains(sources, source) {
+			problem(source, "in the draw sequence but not in Sources, so its balance is never started or reported")
+		}
+	}
+	for _, source := range sources {
+		if source != nil && source.paysIncome && !slices.Contains(drawn, source) {
+			problem(source, "pays income but is not in the draw sequence, so its income is never used")
+		}
+	}
+	for _, source := range s.TaxPaymentSequence {
+		if source == nil {
+			problem(nil, "the tax payment sequence includes a nil source")
+		} else if !slices.Contains(sources, source) {
+			problem(source, "in the tax payment sequence but not in Sources, so its balance is never started or reported")
+		}
+	}
+
+	// Visit the tax accounts in a fixed order so that the problems are always listed in the same order.
+	taxed := []*Source{}
+	for source := range s.TaxAccounts {
+		taxed = append(taxed, source)
+	}
+	slices.SortFunc(taxed, func(a, b *Source) int {
+		if a == nil || b == nil {
+			return 0
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, source := range taxed {
+		ta := s.TaxAccounts[source]
+		if source == nil || ta == nil {
+			problem(source, "the tax accounts include a nil source or account")
+			continue
+		}
+		if !slices.Contains(sources, source) {
+			problem(source, "has tax account %s but is not in Sources", ta.Name)
+		}
+	}
+	for _, source := range sources {
+		if source != nil && source.taxable && s.TaxAccounts[source] == nil {
+			problem(source, "is taxable but has no tax account, so it would be drawn tax free")
+		}
+	}
+
+	regimes := []TaxRegime{}
+	for i, tr := range s.TaxRegimes {
+		if tr == nil {
+			problem(nil, "tax regime %d is nil", i+1)
+			continue
+		}
+		regimes = append(regimes, *tr)
+		validateRegime(fmt.Sprintf("tax regime %d", i+1), *tr, problem)
+	}
+	for _, ta := range s.taxAccounts() {
+		if !slices.ContainsFunc(regimes, ta.regime.same) {
+			validateRegime("the regime of tax account "+ta.Name, ta.regime, problem)
+			problem(nil, "the regime of tax account %s is not in TaxRegimes, so its bands will not rise each year", ta.Name)
+		}
+	}
+	for _, source := range sources {
+		if source == nil || source.ni == nil {
+			continue
+		}
+		if !slices.ContainsFunc(regimes, source.ni.same) {
+			validateRegime("the National Insurance regime of "+source.Name, source.ni.TaxRegime, problem)
+			problem(source, "its National Insurance regime is not in TaxRegimes, so its bands will not rise each year")
+		}
+	}
+	for _, source := range sources {
+		if source == nil || source.saleTax == nil {
+			continue
+		}
+		if ta := source.saleTax; !slices.ContainsFunc(regimes, ta.regime.same) {
+			validateRegime("the regime of sale tax account "+ta.Name, ta.regime, problem)
+			problem(source, "the regime of its sale tax account %s is not in TaxRegimes, so its bands will not rise each year", ta.Name)
+		}
+	}
+	for _, source := range sources {
+		if source != nil && source.fees != nil {
+			validateFees(source, source.fees, problem)
+		}
+	}
+
+	for i, v := range s.InflationLinkedVariables {
+		if v == nil {
+			problem(nil, "inflation linked variable %d is nil", i+1)
+		}
+	}
+	if c := s.Care; c != nil {
+		if c.StartYear == 0 && c.ProbabilityByAge == nil {
+			problem(nil, "care starts at random but has no ProbabilityByAge")
+		}
+		if mt := c.MeansTest; mt != nil && mt.LowerCapitalLimit > mt.UpperCapitalLimit {
+			problem(nil, "the care means test's lower capital limit is above its upper limit")
+		}
+		if mt := c.MeansTest; mt != nil && mt.PersonalExpensesAllowance < 0 {
+			problem(nil, "the care means test's personal expenses allowance is negative")
+		}
+	}
+
+	if len(ps) == 0 {
+		return nil
+	}
+	return ps
+}
+
+// validateEntry checks an entry in the draw sequence, looking through combinations such as Seq and Split
+// to the sources they draw on, which it adds to drawn if they are not already there.
+func validateEntry(entry *Source, drawn []*Source, problem func(source *Source, format string, a ...any)) []*Source {
+	if entry == nil {
+		problem(nil, "the draw sequence includes a nil source")
+		return drawn
+	}
+	if entry.splitPcts != nil {
+		total := int64(0)
+		for _, pct := range entry.splitPcts {
+			if pct < 0 {
+				problem(entry, "split percentage %d is negative", pct)
+			}
+			total += pct
+		}
+		if total != 100 {
+			problem(entry, "split percentages add up to %d rather than 100", total)
+		}
+	}
+	if entry.wraps != nil {
+		for _, source := range entry.wraps {
+			drawn = validateEntry(source, drawn, problem)
+		}
+		return drawn
+	}
+	if !slices.Contains(drawn, entry) {
+		drawn = append(drawn, entry)
+	}
+	return drawn
+}
+
+// taxAccounts returns the distinct tax accounts of the scenario, sorted by name.
+func (s *DrawScenario) taxAccounts() []*TaxAccount {
+	tas := []*TaxAccount{}
+	for _, ta := range s.TaxAccounts {
+		if ta != nil && !slices.Contains(tas, ta) {
+			tas = append(tas, ta)
+		}
+	}
+	slices.SortFunc(tas, func(a, b *TaxAccount) int { return strings.Compare(a.Name, b.Name) })
+	return tas
+}
+
+// same reports whether the regimes share their bands, as a tax account's copy of a regime does.
+func (tr TaxRegime) same(other TaxRegime) bool {
+	return len(tr.Rates) > 0 && len(other.Rates) > 0 && &tr.Rates[0] == &other.Rates[0]
+}
+
+// validateFees checks that the tiers of a fee schedule rise strictly and that no charge is negative.
+func validateFees(source *Source, fs *FeeSchedule, problem func(source *Source, format string, a ...any)) {
+	for i, t := range fs.Tiers {
+		if t.pct < 0 {
+			problem(source, "fee tier %d has a negative percentage", i+1)
+		}
+		if i > 0 && t.upper <= fs.Tiers[i-1].upper {
+			problem(source, "fee tier %d's upper bound %d is not above the previous tier's %d", i+1, t.upper, fs.Tiers[i-1].upper)
+		}
+	}
+	if fs.AnnualFlatFee < 0 || fs.AnnualCap < 0 || fs.FundOCFPct < 0 {
+		problem(source, "the fee schedule has a negative flat fee, cap or fund charge")
+	}
+}
+
+// validateRegime checks that the bands of a regime rise strictly and cover any amount.
+func validateRegime(name string, tr TaxRegime, problem func(source *Source, format string, a ...any)) {
+	if len(tr.Rates) == 0 {
+		problem(nil, "%s has no bands", name)
+		return
+	}
+	for i, rb := range tr.Rates {
+		if rb.rate < 0 {
+			problem(nil, "%s has a negative rate in band %d", name, i+1)
+		}
+		if i > 0 && rb.upper <= tr.Rates[i-1].upper {
+			problem(nil, "%s has band %d's upper bound %d not above the previous band's %d", name, i+1, rb.upper, tr.Rates[i-1].upper)
+		}
+	}
+	if last := tr.Rates[len(tr.Rates)-1]; last.upper != HighUpperBound {
+		problem(nil, "%s does not cover amounts above %d; its last band should have HighUpperBound", name, last.upper)
+	}
+}
@@ -0,0 +1,102 @@
+package drawdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		define func(b *Builder)
+		want   []string // The reasons of the problems found, in part.
+	}{
+		{"valid", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			ni := b.NIWith(class1NIRegime())
+			earnings := b.AddSource(NewEarnings("Earnings", 20000, &b.Rates().AnnualInflationRate, 0, 1, 5, ni), incomeTax)
+			cash := b.AddSource(NewSavingsAccount("Savings", 1000, &b.Rates().SavingsGrowthRate))
+			cgt := b.TaxWith("Capital Gains Tax", NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(HighUpperBound, 24)}))
+			b.DrawFrom(earnings, b.AddSource(NewRentalProperty("Flat", rentalTerms(cash, cgt)), incomeTax), cash)
+		}, nil},
+		{"income not drawn", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			b.AddSource(NewStatePension("State Pension", 11000, 2.5, 1), incomeTax)
+			b.DrawFrom(b.AddSource(NewSavingsAccount("Savings", 1000, &b.Rates().SavingsGrowthRate)))
+		}, []string{"(State Pension): pays income but is not in the draw sequence"}},
+		{"income drawn within a combination", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			sp := b.AddSource(NewStatePension("State Pension", 11000, 2.5, 1), incomeTax)
+			savings := b.AddSource(NewSavingsAccount("Savings", 1000, &b.Rates().SavingsGrowthRate))
+			b.DrawFrom(Seq(new(int64), sp, savings))
+		}, nil},
+		{"National Insurance bands not rising", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			ni := NewNIRegime(NIClass1, []RateBound{NewRateBound(50270, 8), NewRateBound(12570, 0), NewRateBound(HighUpperBound, 2)})
+			b.DrawFrom(b.AddSource(NewEarnings("Earnings", 20000, &b.Rates().AnnualInflationRate, 0, 1, 5, &ni), incomeTax))
+		}, []string{
+			"the National Insurance regime of Earnings has band 2's upper bound 12570 not above the previous band's 50270",
+			"(Earnings): its National Insurance regime is not in TaxRegimes",
+		}},
+		{"National Insurance bands not covering every amount", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			ni := NewNIRegime(NIClass1, []RateBound{NewRateBound(12570, 0), NewRateBound(50270, 8)})
+			b.DrawFrom(b.AddSource(NewEarnings("Earnings", 20000, &b.Rates().AnnualInflationRate, 0, 1, 5, &ni), incomeTax))
+		}, []string{
+			"the National Insurance regime of Earnings does not cover amounts above 50270",
+			"(Earnings): its National Insurance regime is not in TaxRegimes",
+		}},
+		{"National Insurance regime not registered", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			ni := class1NIRegime()
+			b.DrawFrom(b.AddSource(NewEarnings("Earnings", 20000, &b.Rates().AnnualInflationRate, 0, 1, 5, &ni), incomeTax))
+		}, []string{"(Earnings): its National Insurance regime is not in TaxRegimes, so its bands will not rise each year"}},
+		{"sale tax bands not rising", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			cgt := NewTaxAccount("Capital Gains Tax", NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(1000, 24), NewRateBound(HighUpperBound, 24)}))
+			cash := b.AddSource(NewSavingsAccount("Cash", 1000, &b.Rates().SavingsGrowthRate))
+			b.DrawFrom(b.AddSource(NewRentalProperty("Flat", rentalTerms(cash, cgt)), incomeTax), cash)
+		}, []string{
+			"the regime of sale tax account Capital Gains Tax has band 2's upper bound 1000 not above the previous band's 3000",
+			"(Flat): the regime of its sale tax account Capital Gains Tax is not in TaxRegimes, so its bands will not rise each year",
+		}},
+		{"sale tax bands not covering every amount", func(b *Builder) {
+			incomeTax := b.TaxWith("Income Tax", incomeTaxRegime())
+			cgt := b.TaxWith("Capital Gains Tax", NewTaxRegime([]RateBound{NewRateBound(3000, 0), NewRateBound(50000, 24)}))
+			cash := b.AddSource(NewSavingsAccount("Cash", 1000, &b.Rates().SavingsGrowthRate))
+			b.DrawFrom(b.AddSource(NewRentalProperty("Flat", rentalTerms(cash, cgt)), incomeTax), cash)
+		}, []string{"tax regime 2 does not cover amounts above 50000"}},
+		{"fee tiers not rising", func(b *Builder) {
+			isa := NewInvestmentAccount("ISA", 1000, &b.Rates().InvestmentGrowthRate).WithFees(&FeeSchedule{
+				Tiers: []FeeTier{NewFeeTier(250000, 0.25), NewFeeTier(250000, 0.1), NewFeeTier(HighUpperBound, -0.1)},
+			})
+			b.DrawFrom(b.AddSource(isa))
+		}, []string{
+			"(ISA): fee tier 2's upper bound 250000 is not above the previous tier's 250000",
+			"(ISA): fee tier 3 has a negative percentage",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder()
+			tt.define(b)
+			err := b.s.Validate()
+			var ps Problems
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("got %v, want no problems", err)
+				}
+				return
+			}
+			if !errors.As(err, &ps) || len(ps) != len(tt.want) {
+				t.Fatalf("got %v, want %d problems", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(ps[i].Error(), want) {
+					t.Errorf("problem %d: got %q, want %q", i+1, ps[i].Error(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+//go:build js && wasm
+
+// Command drawdown-wasm exposes the drawdown engine to JavaScript when built with GOOS=js GOARCH=wasm.
+//
+// It sets a global drawdown object with three functions:
+//
+//	drawdown.scenarios()        the names of the built-in scenarios and of those loaded
+//	drawdown.load(definition)   load a scenario defined in JSON (scenario.Spec), which can then be run by name
+//	drawdown.run(request)       run a scenario and return its inputs, summary, events and history
+//
+// The request is a server.RunRequest, as an object or its JSON, such as
+// {Scenario: "Ivy", Years: 30, Year1AnnualIncome: 35000, Rates: {InvestmentGrowthRate: 3.5}}.
+// load returns {Name: "..."} and run returns the result, or either returns {Error: "..."} if it cannot.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"syscall/js"
+
+	"github.com/vextasy/drawdown/report"
+	"github.com/vextasy/drawdown/scenario"
+	"github.com/vextasy/drawdown/server"
+)
+
+// loaded are the scenarios loaded from JSON, by name.
+var loaded = map[string]*scenario.Spec{}
+
+func main() {
+	js.Global().Set("drawdown", js.ValueOf(map[string]any{
+		"scenarios": js.FuncOf(scenarios),
+		"load":      js.FuncOf(load),
+		"run":       js.FuncOf(run),
+	}))
+	select {}
+}
+
+func scenarios(this js.Value, args []js.Value) any {
+	names := []any{}
+	for _, name := range scenario.Names() {
+		names = append(names, name)
+	}
+	more := []string{}
+	for name := range loaded {
+		if scenario.New(name) == nil {
+			more = append(more, name)
+		}
+	}
+	slices.Sort(more)
+	for _, name := range more {
+		names = append(names, name)
+	}
+	return js.ValueOf(names)
+}
+
+func load(this js.Value, args []js.Value) any {
+	name, err := loadJSON(args)
+	if err != nil {
+		return js.ValueOf(map[string]any{"Error": err.Error()})
+	}
+	return js.ValueOf(map[string]any{"Name": name})
+}
+
+// loadJSON loads the definition given as the first argument, replacing any loaded before with the same name,
+// and returns its name. A loaded scenario is run in place of a built-in one of the same name.
+func loadJSON(args []js.Value) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("load takes a single definition")
+	}
+	sp, err := scenario.Parse([]byte(jsonText(args[0])))
+	if err != nil {
+		return "", err
+	}
+	if _, err := sp.Build(); err != nil {
+		return "", err
+	}
+	loaded[sp.Name] = sp
+	return sp.Name, nil
+}
+
+// jsonText returns v if it is a string, or else its JSON.
+func jsonText(v js.Value) string {
+	if v.Type() != js.TypeString {
+		v = js.Global().Get("JSON").Call("stringify", v)
+	}
+	return v.String()
+}
+
+func run(this js.Value, args []js.Value) any {
+	out, err := runJSON(args)
+	if err != nil {
+		return js.ValueOf(map[string]any{"Error": err.Error()})
+	}
+	return js.Global().Get("JSON").Call("parse", out)
+}
+
+// runJSON runs the request given as the first argument and returns the result as JSON.
+func runJSON(args []js.Value) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("run takes a single request")
+	}
+	var req server.RunRequest
+	if err := json.Unmarshal([]byte(jsonText(args[0])), &req); err != nil {
+		return "", err
+	}
+	if sp, ok := loaded[req.Scenario]; ok && req.Definition == nil {
+		req.Definition = sp
+	}
+	s, err := req.NewScenario()
+	if err != nil {
+		return "", err
+	}
+	if req.Years < 1 {
+		return "", fmt.Errorf("years must be at least 1")
+	}
+	s.WithRates(req.Rates).WithContinueAfterShortfall(req.ContinueAfterShortfall).WithSeed(req.Seed)
+	iterate := s.Iterate
+	if req.Monthly {
+		iterate = s.IterateMonthly
+	}
+	result, err := iterate(req.Years, req.Year1AnnualIncome)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := report.WriteJSON(&b, req.Inputs(), result); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vextasy/drawdown/report"
+	"github.com/vextasy/drawdown/scenario"
+)
+
+func doCompare(args []string) {
+	fs := newFlagSet("compare", "Run several scenarios with the same rates, write the differences in each year to a CSV file\nand print which does best.")
+	o := addRunFlags(fs, "")
+	o.addRateFlags(fs)
+	names := fs.String("scenarios", "Ivy,Simple", "a comma separated list of the built-in scenarios, or the one defined in -file, to compare; differences are from the first")
+	csvPath := fs.String("csv", "compare.csv", "write the totals and differences of each year to the given `file`")
+	o.parse(fs, args)
+
+	defined := o.Definition
+	cs := []report.Compared{}
+	for _, name := range strings.Split(*names, ",") {
+		name = strings.TrimSpace(name)
+		o.Definition = nil
+		if defined != nil && strings.EqualFold(name, defined.Name) {
+			o.Definition = defined
+		} else if scenario.New(name) == nil {
+			fmt.Fprintf(os.Stderr, "drawdown compare: unknown scenario %q\n", name)
+			listScenarios(os.Stderr)
+			os.Exit(2)
+		}
+		o.Scenario = name
+		result, err := o.iterate(o.Rates, o.Year1AnnualIncome)
+		if err != nil {
+			fatal(fmt.Errorf("%s: %w", name, err))
+		}
+		cs = append(cs, report.Compared{Name: name, Result: result})
+	}
+	if len(cs) < 2 {
+		fmt.Fprintln(os.Stderr, "drawdown compare: name at least two scenarios to compare")
+		os.Exit(2)
+	}
+
+	writeFile(*csvPath, func(f *os.File) error {
+		return report.WriteComparisonCSV(f, cs)
+	})
+	if err := report.WriteComparison(os.Stdout, cs); err != nil {
+		fatal(err)
+	}
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
-	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
-	drawdown "github.com/vextasy/drawdown/app"
 	"github.com/vextasy/drawdown/scenario"
 )
 
+// The defaults for the flags of the commands.
 const (
 	Years             = 30
 	Year0AnnualIncome = 35000
@@ -18,71 +20,116 @@ const (
 	AnnualInflationRate      = 2.0  // %
 	PlatformChargeRate       = 0.25 // The % charge for using a platform as a percentage of the balance.
 	TaxBandAnnualPctIncrease = 0.5  // %
+
+	CareCostInflationRate = AnnualInflationRate // Care fees rise with prices unless -cci says otherwise.
 )
 
+// commands are the subcommands of drawdown, in the order in which they are listed by help.
+var commands = []struct {
+	name    string
+	summary string
+	do      func(args []string)
+}{
+	{"run", "run a scenario and write the transactions of each year", doRun},
+	{"sweep", "run a scenario over every combination of several values of the rates", doSweep},
+	{"montecarlo", "run a scenario many times with randomly drawn rates and summarise the outcomes", doMonteCarlo},
+	{"compare", "run several scenarios with the same rates and compare them year by year", doCompare},
+	{"solve", "find the highest first year need that a scenario can meet in every year", doSolve},
+	{"validate", "check scenarios for mistakes", doValidate},
+	{"serve", "serve an HTTP JSON API for running scenarios", doServe},
+}
+
 func main() {
-	summary := flag.Bool("s", false, "produce a summary")
-	flag.Parse()
-	if *summary {
-		doSummary()
-	} else {
-		doDrawdown()
+	args := os.Args[1:]
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelp(args[0])) {
+		doRun(args)
+		return
+	}
+	for _, c := range commands {
+		if args[0] == c.name {
+			c.do(args[1:])
+			return
+		}
+	}
+	if isHelp(args[0]) {
+		usage(os.Stdout)
+		return
 	}
+	fmt.Fprintf(os.Stderr, "drawdown: unknown command %q\n\n", args[0])
+	usage(os.Stderr)
+	os.Exit(2)
 }
-func doDrawdown() {
 
-	s := scenario.NewIvyDrawScenario().WithRates(drawdown.DrawRates{
-		InvestmentGrowthRate:     InvestmentGrowthRate,
-		SavingsGrowthRate:        SavingsGrowthRate,
-		AnnualInflationRate:      AnnualInflationRate,
-		PlatformChargeRate:       PlatformChargeRate,
-		TaxBandAnnualPctIncrease: TaxBandAnnualPctIncrease,
-	})
+func isHelp(arg string) bool {
+	switch arg {
+	case "help", "-h", "-help", "--help":
+		return true
+	}
+	return false
+}
 
-	transactions := s.Iterate(Years, Year0AnnualIncome)
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: drawdown [command] [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nWith no command, drawdown runs. Use \"drawdown <command> -help\" for the flags of a command.\n\n")
+	listScenarios(w)
+}
 
-	if len(transactions) > 0 {
+func listScenarios(w io.Writer) {
+	fmt.Fprintf(w, "Built-in scenarios: %s\n", strings.Join(scenario.Names(), ", "))
+}
 
-		file, err := os.Create("drawdown.csv")
-		if err != nil {
-			panic(err)
-		}
-		defer file.Close()
-		fmt.Fprintf(file, "Year,Source,Amount,Tax,Tax Raised,Balance\n")
-		for _, t := range transactions {
-			fmt.Fprintf(file, "%d,\"%s\",%v,%v,%v,%v\n", t.Year, t.Source, t.Amount, t.Tax, t.TaxRaised, t.Balance)
-		}
-	}
+// fatal reports err and exits.
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "drawdown:", err)
+	os.Exit(1)
+}
 
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
 }
 
-func doSummary() {
-	file, err := os.Create("summary.csv")
+// writeFile creates the named file and writes to it with write, exiting if either fails.
+func writeFile(name string, write func(f *os.File) error) {
+	f, err := os.Create(name)
 	if err != nil {
-		panic(err)
+		fatal(err)
+	}
+	defer f.Close()
+	if err := write(f); err != nil {
+		fatal(err)
+	}
+}
+
+// outFormat returns "json" or "ndjson" according to the extension of the -out file name,
+// or "" if the extension is not recognised. The name "-" stands for JSON on stdout.
+func outFormat(name string) string {
+	if name == "-" {
+		return "json"
+	}
+	switch filepath.Ext(name) {
+	case ".json":
+		return "json"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
 	}
-	defer file.Close()
-	fmt.Fprintf(file, "Investment Growth Rate,Savings Growth Rate,Annual Inflation Rate,Platform Charge Rate,Tax Band Annual Percentage Increase,Total Withdrawn,Tax Paid,Final Balance,Final Year\n")
+	return ""
+}
 
-	for _, igr := range []float64{0.0, 0.5, 1.0, 2.0, 3.0, 4.0, 5.0, 8.0} { // Investment Growth Rate
-		for _, sgr := range []float64{0.0, 0.5, 1.0, 2.0, 3.0, 4.0, 5.0, 8.0} { // Savings Growth Rate
-			for _, air := range []float64{2.0, 2.5, 3, 4, 5} { // Annual Inflation Rate
-				for _, pcr := range []float64{0.1, 0.25, 0.5} { // Platform Charge Rate
-					for _, tbi := range []float64{0.0, 0.5, 1.0, 2.0} { // Tax Band Annual Percentage Increase
-						s := scenario.NewComplexDrawScenario().WithRates(drawdown.DrawRates{
-							InvestmentGrowthRate:     igr,
-							SavingsGrowthRate:        sgr,
-							AnnualInflationRate:      air,
-							PlatformChargeRate:       pcr,
-							TaxBandAnnualPctIncrease: tbi,
-						})
-						transactions := s.Iterate(Years, Year0AnnualIncome)
-						summary := transactions.Summary()
-						fmt.Fprintf(file, "igr_%.2f, sgr_%.2f, air_%.2f, pcr_%.2f, tbi_%.2f, %d, %d, %d, %d\n", igr, sgr, air, pcr, tbi, summary.TotalWithdrawn, summary.TotalTaxPaid, summary.FinalBalance, summary.FinalYear)
-					}
-				}
-			}
+// writeOut writes to the named file, or to stdout if the name is "-", in the format chosen by outFormat.
+func writeOut(name string, write func(w io.Writer, ndjson bool) error) {
+	ndjson := outFormat(name) == "ndjson"
+	if name == "-" {
+		if err := write(os.Stdout, ndjson); err != nil {
+			fatal(err)
 		}
+		return
 	}
-
+	writeFile(name, func(f *os.File) error {
+		return write(f, ndjson)
+	})
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	drawdown "github.com/vextasy/drawdown/app"
+	"github.com/vextasy/drawdown/report"
+	"github.com/vextasy/drawdown/scenario"
+	"github.com/vextasy/drawdown/server"
+)
+
+// rateFlags are the flags which set each of the DrawRates, named as in report.RateAxes.
+var rateFlags = []struct {
+	name  string
+	value float64 // The default.
+	field func(r *drawdown.DrawRates) *float64
+}{
+	{"igr", InvestmentGrowthRate, func(r *drawdown.DrawRates) *float64 { return &r.InvestmentGrowthRate }},
+	{"sgr", SavingsGrowthRate, func(r *drawdown.DrawRates) *float64 { return &r.SavingsGrowthRate }},
+	{"air", AnnualInflationRate, func(r *drawdown.DrawRates) *float64 { return &r.AnnualInflationRate }},
+	{"pcr", PlatformChargeRate, func(r *drawdown.DrawRates) *float64 { return &r.PlatformChargeRate }},
+	{"tbi", TaxBandAnnualPctIncrease, func(r *drawdown.DrawRates) *float64 { return &r.TaxBandAnnualPctIncrease }},
+	{"hpg", 0, func(r *drawdown.DrawRates) *float64 { return &r.HousePriceGrowthRate }},
+	{"cci", CareCostInflationRate, func(r *drawdown.DrawRates) *float64 { return &r.CareCostInflationRate }},
+}
+
+// runOptions are the flags shared by the commands which run a scenario.
+// They have the fields of a serve /run request so that they can also be read from a file.
+type runOptions struct {
+	server.RunRequest
+	file string
+}
+
+// newFlagSet returns a flag set for the named command whose help also lists the built-in scenarios.
+func newFlagSet(name string, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintf(w, "Usage: drawdown %s [flags]\n\n%s\n\nFlags:\n", name, synopsis)
+		fs.PrintDefaults()
+		fmt.Fprintln(w)
+		listScenarios(w)
+	}
+	return fs
+}
+
+// addRunFlags adds the flags of a run to fs. The rates are added separately, by addRateFlags,
+// since a sweep takes lists of them. If scenarioName is empty there is no -scenario flag,
+// as the command chooses the scenarios to run itself.
+func addRunFlags(fs *flag.FlagSet, scenarioName string) *runOptions {
+	o := &runOptions{}
+	if scenarioName != "" {
+		fs.StringVar(&o.Scenario, "scenario", scenarioName, "the `name` of the built-in scenario to run")
+	}
+	fs.StringVar(&o.file, "file", "", "read the scenario, years, income, rates and options from a JSON `file` with the fields of a serve /run request, or read a scenario described in JSON; other flags override it")
+	fs.IntVar(&o.Years, "years", Years, "the number of years to run for")
+	fs.IntVar(&o.Year1AnnualIncome, "income", Year0AnnualIncome, "the need in the first year, which then rises with inflation")
+	fs.BoolVar(&o.Monthly, "monthly", false, "simulate month by month rather than year by year")
+	fs.BoolVar(&o.Monthly, "m", false, "short for -monthly")
+	fs.BoolVar(&o.ContinueAfterShortfall, "continue", false, "continue after a year in which the need cannot be met")
+	fs.BoolVar(&o.ContinueAfterShortfall, "c", false, "short for -continue")
+	fs.Int64Var(&o.Seed, "seed", 0, "the seed of random events, such as the start of care; the same seed gives the same run")
+	return o
+}
+
+// addRateFlags adds a flag to fs for each of the rates.
+func (o *runOptions) addRateFlags(fs *flag.FlagSet) {
+	for _, rf := range rateFlags {
+		fs.Float64Var(rf.field(&o.Rates), rf.name, rf.value, report.RateAxes[rf.name].Label+" %")
+	}
+}
+
+// parse parses the arguments, as parseFlags does, and stops if the scenario to run
+// is not a built-in one or cannot be built.
+func (o *runOptions) parse(fs *flag.FlagSet, args []string) {
+	o.parseFlags(fs, args)
+	if o.Definition != nil {
+		if _, err := o.Definition.Build(); err != nil {
+			fatal(fmt.Errorf("%s: %s: %w", o.file, o.Definition.Name, err))
+		}
+		o.Scenario = o.Definition.Name
+		return
+	}
+	if o.Scenario != "" && scenario.New(o.Scenario) == nil {
+		fmt.Fprintf(os.Stderr, "drawdown %s: unknown scenario %q\n", fs.Name(), o.Scenario)
+		listScenarios(os.Stderr)
+		os.Exit(2)
+	}
+}
+
+// parseFlags parses the arguments. Any -file is read before the flags are applied again,
+// so that those given on the command line take precedence over it.
+// A scenario defined in the file, or as its Definition, is kept unless -scenario names a built-in one.
+func (o *runOptions) parseFlags(fs *flag.FlagSet, args []string) {
+	fs.Parse(args)
+	if o.file != "" {
+		o.readFile()
+		fs.Parse(args)
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "scenario" {
+				o.Definition = nil
+			}
+		})
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "drawdown %s: unexpected arguments %q\n", fs.Name(), fs.Args())
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+// readFile reads -file, which holds either the fields of a serve /run request or a scenario.Spec.
+// If it can be read as neither, the error reported is that of the Spec if the file has any of a Spec's
+// own fields, such as Sources, and that of the request otherwise.
+func (o *runOptions) readFile() {
+	b, err := os.ReadFile(o.file)
+	if err != nil {
+		fatal(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&o.RunRequest)
+	if err == nil {
+		return
+	}
+	sp, specErr := scenario.Parse(b)
+	if specErr != nil {
+		if isSpec(b) {
+			err = specErr
+		}
+		fatal(fmt.Errorf("%s: %w", o.file, err))
+	}
+	o.Definition = sp
+}
+
+// isSpec reports whether the JSON object b has a field of a scenario.Spec which a serve /run request does not have.
+func isSpec(b []byte) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(b, &fields) != nil {
+		return false
+	}
+	request := reflect.TypeFor[server.RunRequest]()
+	for _, f := range reflect.VisibleFields(reflect.TypeFor[scenario.Spec]()) {
+		if _, ok := request.FieldByName(f.Name); ok {
+			continue
+		}
+		for name := range fields {
+			if strings.EqualFold(name, f.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// iterate runs a new instance of the scenario with the given rates and year 1 need.
+func (o *runOptions) iterate(rates drawdown.DrawRates, income int) (drawdown.DrawResult, error) {
+	s, err := o.NewScenario()
+	if err != nil {
+		return drawdown.DrawResult{}, err
+	}
+	s.WithRates(rates).WithContinueAfterShortfall(o.ContinueAfterShortfall).WithSeed(o.Seed)
+	if o.Monthly {
+		return s.IterateMonthly(o.Years, income)
+	}
+	return s.Iterate(o.Years, income)
+}
+
+// rateList is a flag holding a comma separated list of rates.
+type rateList []float64
+
+func (l *rateList) String() string {
+	s := make([]string, len(*l))
+	for i, v := range *l {
+		s[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *rateList) Set(value string) error {
+	*l = nil
+	for _, f := range strings.Split(value, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	drawdown "github.com/vextasy/drawdown/app"
+	"github.com/vextasy/drawdown/report"
+)
+
+// monteCarloStdDevs are the default standard deviations of each rate in a Monte Carlo run, in the order of rateFlags.
+// A rate holds for the whole of a run, so these are deviations of the average rate over many years.
+var monteCarloStdDevs = []float64{
+	2.0, // Investment Growth Rate
+	1.0, // Savings Growth Rate
+	1.0, // Annual Inflation Rate
+	0.0, // Platform Charge Rate
+	0.0, // Tax Band Annual Percentage Increase
+	0.0, // House Price Growth Rate
+	0.0, // Care Cost Inflation Rate
+}
+
+func doMonteCarlo(args []string) {
+	fs := newFlagSet("montecarlo", "Run a scenario many times, each with rates drawn at random from normal distributions whose means are\nthe rates given, write a summary of each run to a CSV file and print the spread of the outcomes.")
+	o := addRunFlags(fs, "Ivy")
+	o.addRateFlags(fs)
+	mc := drawdown.MonteCarlo{}
+	for k, rf := range rateFlags {
+		fs.Float64Var(rf.field(&mc.StdDev), rf.name+"-sd", monteCarloStdDevs[k], "the standard deviation of the "+report.RateAxes[rf.name].Label+" % of a run")
+	}
+	fs.IntVar(&mc.Runs, "runs", 1000, "the number of runs")
+	fs.Int64Var(&mc.Seed, "rate-seed", 1, "the seed of the random rates; the same seed gives the same rates, and -seed the same random events in each run")
+	csvPath := fs.String("csv", "montecarlo.csv", "write the rates and summary of each run to the given `file`")
+	outPath := fs.String("out", "", "also write the runs and their summary as JSON to the given `file`, or as NDJSON if it ends in .ndjson or .jsonl, or as JSON to stdout if it is -")
+	o.parse(fs, args)
+	if *outPath != "" && outFormat(*outPath) == "" {
+		fmt.Fprintf(os.Stderr, "drawdown montecarlo: unknown output format for %q: want .json, .ndjson or .jsonl\n", *outPath)
+		os.Exit(2)
+	}
+	if mc.Runs < 1 {
+		fmt.Fprintln(os.Stderr, "drawdown montecarlo: -runs must be at least 1")
+		os.Exit(2)
+	}
+	mc.Mean = o.Rates
+
+	rows := []report.SweepRow{}
+	summaries := []drawdown.DrawSummary{}
+	writeFile(*csvPath, func(file *os.File) error {
+		writeSummaryHeader(file)
+		// Each run's random events are drawn from -seed, independently of the rates.
+		seeds := drawdown.MonteCarlo{Runs: mc.Runs, Seed: o.Seed}.Seeds()
+		for i, rates := range mc.Rates() {
+			run := *o
+			run.Seed = seeds[i]
+			result, err := run.iterate(rates, o.Year1AnnualIncome)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", rateLabel(rates), err)
+				continue
+			}
+			summary := result.Summary()
+			rows = append(rows, report.SweepRow{Rates: rates, Summary: summary})
+			summaries = append(summaries, summary)
+			writeSummaryRow(file, rates, summary)
+		}
+		return nil
+	})
+
+	s := drawdown.SummarizeMonteCarlo(summaries)
+	if *outPath != "" {
+		writeOut(*outPath, func(w io.Writer, ndjson bool) error {
+			if ndjson {
+				return report.WriteMonteCarloNDJSON(w, o.Inputs(), mc, s, rows)
+			}
+			return report.WriteMonteCarloJSON(w, o.Inputs(), mc, s, rows)
+		})
+	}
+	if *outPath != "-" {
+		if err := report.WriteMonteCarlo(os.Stdout, o.Inputs(), mc, s); err != nil {
+			fatal(err)
+		}
+	}
+}
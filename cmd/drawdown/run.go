@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vextasy/drawdown/report"
+)
+
+func doRun(args []string) {
+	fs := newFlagSet("run", "Run a scenario and write the transactions of each year to a CSV file.")
+	o := addRunFlags(fs, "Ivy")
+	o.addRateFlags(fs)
+	csvPath := fs.String("csv", "drawdown.csv", "write the transactions to the given `file`")
+	netWorthPath := fs.String("networth", "networth.csv", "write the net worth at the end of each year to the given `file`")
+	format := fs.String("format", "long", "the layout of the transactions: long, with a row for each source in each year, or wide, with a row for each year")
+	explainYear := fs.Int("explain", 0, "print an explanation of every movement of money in the given `year`")
+	todaysMoney := fs.Bool("real", false, "give amounts in today's money rather than nominal values")
+	htmlPath := fs.String("html", "", "also write an HTML report with charts to the given `file`")
+	xlsxPath := fs.String("xlsx", "", "also write an Excel workbook of the drawdown to the given `file`")
+	outPath := fs.String("out", "", "also write the results as JSON to the given `file`, or as NDJSON if it ends in .ndjson or .jsonl, or as JSON to stdout if it is -")
+	table := fs.Bool("table", false, "print a table of each year, with sparklines, to stdout")
+	o.parse(fs, args)
+	if *format != "long" && *format != "wide" {
+		fmt.Fprintf(os.Stderr, "drawdown run: unknown format %q: want long or wide\n", *format)
+		os.Exit(2)
+	}
+	if *outPath != "" && outFormat(*outPath) == "" {
+		fmt.Fprintf(os.Stderr, "drawdown run: unknown output format for %q: want .json, .ndjson or .jsonl\n", *outPath)
+		os.Exit(2)
+	}
+
+	in := o.Inputs()
+	in.Real = *todaysMoney
+	result, err := o.iterate(o.Rates, o.Year1AnnualIncome)
+	if err != nil {
+		fatal(err)
+	}
+	if *todaysMoney {
+		result = result.Real()
+	}
+	for _, e := range result.Events {
+		fmt.Fprintln(os.Stderr, e)
+	}
+	if *explainYear > 0 {
+		if err := result.Ledger.Explain(os.Stdout, *explainYear); err != nil {
+			fatal(err)
+		}
+	}
+	if *table {
+		if err := report.WriteTable(os.Stdout, result, isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""); err != nil {
+			fatal(err)
+		}
+	}
+	transactions := result.History
+
+	if *htmlPath != "" {
+		writeFile(*htmlPath, func(f *os.File) error {
+			return report.WriteHTML(f, "Drawdown", result)
+		})
+	}
+	if *xlsxPath != "" {
+		writeFile(*xlsxPath, func(f *os.File) error {
+			return report.WriteXLSX(f, result, in)
+		})
+	}
+	if *outPath != "" {
+		writeOut(*outPath, func(w io.Writer, ndjson bool) error {
+			if ndjson {
+				return report.WriteNDJSON(w, in, result)
+			}
+			return report.WriteJSON(w, in, result)
+		})
+	}
+
+	if len(transactions) == 0 {
+		return
+	}
+	writeFile(*csvPath, func(f *os.File) error {
+		if *format == "wide" {
+			return report.WriteWideCSV(f, result)
+		}
+		cw := csv.NewWriter(f)
+		cw.Write([]string{"Year", "Tax Year", "Ages", "Source", "Opening Balance", "Growth", "Fees", "Deposits", "Withdrawals", "Amount", "Tax", "Tax Raised", "Balance"})
+		for _, t := range transactions {
+			cw.Write([]string{fmt.Sprint(t.Year), t.TaxYear, report.FormatAges(t.Ages), t.Source, fmt.Sprint(t.OpeningBalance), fmt.Sprint(t.Growth), fmt.Sprint(t.Fees), fmt.Sprint(t.Deposits), fmt.Sprint(t.Withdrawals), fmt.Sprint(t.Amount), fmt.Sprint(t.Tax), fmt.Sprint(t.TaxRaised), fmt.Sprint(t.Balance)})
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+
+	unmet := map[int]int64{}
+	for _, sf := range result.Shortfalls() {
+		unmet[sf.Year] += sf.Amount
+	}
+	writeFile(*netWorthPath, func(f *os.File) error {
+		fmt.Fprintf(f, "Year,Tax Year,Ages,Assets,Liabilities,Net Worth,Unmet Need\n")
+		for _, nw := range transactions.NetWorth() {
+			if _, err := fmt.Fprintf(f, "%d,%s,%s,%v,%v,%v,%v\n", nw.Year, nw.TaxYear, report.FormatAges(nw.Ages), nw.Assets, nw.Liabilities, nw.Value(), unmet[nw.Year]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/vextasy/drawdown/server"
+)
+
+// doServe serves the HTTP JSON API until the server fails.
+func doServe(args []string) {
+	fs := newFlagSet("serve", "Serve an HTTP JSON API for running scenarios.")
+	addr := fs.String("addr", "localhost:8080", "the `address` on which to listen")
+	timeout := fs.Duration("timeout", 30*time.Second, "the longest a request may take")
+	fs.Parse(args)
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(*timeout),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintln(os.Stderr, "listening on", *addr)
+	fmt.Fprintln(os.Stderr, srv.ListenAndServe())
+	os.Exit(1)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// MaxSolvedIncome is the highest first year need that solve will consider.
+const MaxSolvedIncome = 10_000_000
+
+func doSolve(args []string) {
+	fs := newFlagSet("solve", "Find the highest first year need, rising with inflation, that a scenario can meet in every year.")
+	o := addRunFlags(fs, "Ivy")
+	o.addRateFlags(fs)
+	o.parse(fs, args)
+	o.ContinueAfterShortfall = false
+
+	// met reports whether the need is met in every year when it starts at income.
+	met := func(income int) bool {
+		result, err := o.iterate(o.Rates, income)
+		if err != nil {
+			fatal(err)
+		}
+		return len(result.Shortfalls()) == 0
+	}
+	if !met(0) {
+		fmt.Printf("%s cannot be run for %d years even with no need\n", o.Scenario, o.Years)
+		os.Exit(1)
+	}
+	// Bisect between an income that is met, lo, and one that is not, hi.
+	lo, hi := 0, max(o.Year1AnnualIncome, 1)
+	for met(hi) {
+		if hi >= MaxSolvedIncome {
+			fmt.Printf("%s meets a need of more than %d in every one of %d years\n", o.Scenario, MaxSolvedIncome, o.Years)
+			return
+		}
+		lo, hi = hi, min(2*hi, MaxSolvedIncome)
+	}
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if met(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	fmt.Printf("%s meets a first year need of up to %d in every one of %d years\n", o.Scenario, lo, o.Years)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	drawdown "github.com/vextasy/drawdown/app"
+	"github.com/vextasy/drawdown/report"
+)
+
+// sweepValues are the default values of each rate in a sweep, in the order of rateFlags.
+var sweepValues = [][]float64{
+	{0.0, 0.5, 1.0, 2.0, 3.0, 4.0, 5.0, 8.0}, // Investment Growth Rate
+	{0.0, 0.5, 1.0, 2.0, 3.0, 4.0, 5.0, 8.0}, // Savings Growth Rate
+	{2.0, 2.5, 3, 4, 5},                      // Annual Inflation Rate
+	{0.1, 0.25, 0.5},                         // Platform Charge Rate
+	{0.0, 0.5, 1.0, 2.0},                     // Tax Band Annual Percentage Increase
+	{0.0},                                    // House Price Growth Rate
+	{CareCostInflationRate},                  // Care Cost Inflation Rate
+}
+
+func doSweep(args []string) {
+	fs := newFlagSet("sweep", "Run a scenario over every combination of the given values of the rates and write a summary of each to a CSV file.")
+	o := addRunFlags(fs, "Simple")
+	values := make([]rateList, len(rateFlags))
+	for k, rf := range rateFlags {
+		values[k] = sweepValues[k]
+		fs.Var(&values[k], rf.name, "a comma separated list of values of the "+report.RateAxes[rf.name].Label+" %")
+	}
+	csvPath := fs.String("csv", "summary.csv", "write the summaries to the given `file`")
+	htmlPath := fs.String("html", "", "also write an HTML report with a heatmap of the final balance to the given `file`")
+	outPath := fs.String("out", "", "also write the summaries as JSON to the given `file`, or as NDJSON if it ends in .ndjson or .jsonl, or as JSON to stdout if it is -")
+	axes := fs.String("axes", "igr,sgr", "the two rates, of "+strings.Join(rateNames(), ", ")+", to use as the axes of the heatmap")
+	o.parse(fs, args)
+	if *outPath != "" && outFormat(*outPath) == "" {
+		fmt.Fprintf(os.Stderr, "drawdown sweep: unknown output format for %q: want .json, .ndjson or .jsonl\n", *outPath)
+		os.Exit(2)
+	}
+	// Check the axes of the heatmap before running the sweep rather than after.
+	xAxis, yAxis, _ := strings.Cut(*axes, ",")
+	for _, axis := range []string{xAxis, yAxis} {
+		if _, ok := report.RateAxes[axis]; *htmlPath != "" && !ok {
+			fmt.Fprintf(os.Stderr, "drawdown sweep: unknown rate axis %q in -axes: want two of %s\n", axis, strings.Join(rateNames(), ", "))
+			os.Exit(2)
+		}
+	}
+
+	rows := []report.SweepRow{}
+	writeFile(*csvPath, func(file *os.File) error {
+		writeSummaryHeader(file)
+		eachRates(values, func(rates drawdown.DrawRates) {
+			result, err := o.iterate(rates, o.Year1AnnualIncome)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", rateLabel(rates), err)
+				return
+			}
+			summary := result.Summary()
+			rows = append(rows, report.SweepRow{Rates: rates, Summary: summary})
+			writeSummaryRow(file, rates, summary)
+		})
+		return nil
+	})
+
+	if *htmlPath != "" {
+		writeFile(*htmlPath, func(f *os.File) error {
+			return report.WriteSweepHTML(f, "Drawdown summary", rows, xAxis, yAxis)
+		})
+	}
+	if *outPath != "" {
+		writeOut(*outPath, func(w io.Writer, ndjson bool) error {
+			if ndjson {
+				return report.WriteSweepNDJSON(w, o.Inputs(), rows)
+			}
+			return report.WriteSweepJSON(w, o.Inputs(), rows)
+		})
+	}
+}
+
+// writeSummaryHeader writes the header of a CSV file of the summaries of runs with different rates.
+func writeSummaryHeader(w io.Writer) {
+	labels := []string{}
+	for _, rf := range rateFlags {
+		labels = append(labels, report.RateAxes[rf.name].Label)
+	}
+	fmt.Fprintf(w, "%s,Total Withdrawn,Tax Raised,Final Balance,Final Year,Shortfall Years,First Shortfall Year,Total Shortfall,Real Final Balance\n", strings.Join(labels, ","))
+}
+
+// writeSummaryRow writes the summary of a run with the given rates as a line of a CSV file.
+func writeSummaryRow(w io.Writer, rates drawdown.DrawRates, summary drawdown.DrawSummary) {
+	fmt.Fprintf(w, "%s, %d, %d, %d, %d, %d, %d, %d, %d\n", rateLabel(rates), summary.TotalWithdrawn, summary.TotalTaxRaised, summary.FinalBalance, summary.FinalYear, summary.ShortfallYears, summary.FirstShortfallYear, summary.TotalShortfall, summary.RealFinalBalance)
+}
+
+// eachRates calls f with every combination of the values of the rates, which are in the order of rateFlags.
+func eachRates(values []rateList, f func(rates drawdown.DrawRates)) {
+	var each func(k int, rates drawdown.DrawRates)
+	each = func(k int, rates drawdown.DrawRates) {
+		if k == len(rateFlags) {
+			f(rates)
+			return
+		}
+		for _, v := range values[k] {
+			*rateFlags[k].field(&rates) = v
+			each(k+1, rates)
+		}
+	}
+	each(0, drawdown.DrawRates{})
+}
+
+// rateLabel names a combination of rates, such as "igr_3.50, sgr_3.00, ...".
+func rateLabel(rates drawdown.DrawRates) string {
+	s := []string{}
+	for _, rf := range rateFlags {
+		s = append(s, fmt.Sprintf("%s_%.2f", rf.name, *rf.field(&rates)))
+	}
+	return strings.Join(s, ", ")
+}
+
+func rateNames() []string {
+	names := []string{}
+	for _, rf := range rateFlags {
+		names = append(names, rf.name)
+	}
+	return names
+}
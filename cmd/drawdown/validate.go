@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vextasy/drawdown/scenario"
+)
+
+func doValidate(args []string) {
+	fs := newFlagSet("validate", "Check scenarios for mistakes, such as sources drawn on but not listed, taxable sources\nwithout a tax account, split percentages that do not add up to 100 and tax bands that do not rise.")
+	o := addRunFlags(fs, "")
+	fs.StringVar(&o.Scenario, "scenario", "", "the `name` of the built-in scenario to check, or all of them if neither it nor -file is given")
+	o.parseFlags(fs, args)
+	if o.Definition != nil {
+		if _, err := o.Definition.Build(); err != nil {
+			fmt.Printf("%s:\n%v\n", o.Definition.Name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: ok\n", o.Definition.Name)
+		return
+	}
+	names := scenario.Names()
+	if o.Scenario != "" {
+		if scenario.New(o.Scenario) == nil {
+			fmt.Fprintf(os.Stderr, "drawdown validate: unknown scenario %q\n", o.Scenario)
+			listScenarios(os.Stderr)
+			os.Exit(2)
+		}
+		names = []string{o.Scenario}
+	}
+	failed := false
+	for _, n := range names {
+		if err := scenario.New(n).Validate(); err != nil {
+			failed = true
+			fmt.Printf("%s:\n%v\n", n, err)
+			continue
+		}
+		fmt.Printf("%s: ok\n", n)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
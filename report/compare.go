@@ -0,0 +1,178 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// Compared is a named result in a comparison.
+type Compared struct {
+	Name   string
+	Result drawdown.DrawResult
+}
+
+// comparison holds the totals of each result for every year run by any of them.
+type comparison struct {
+	years  []int
+	totals [][]yearTotal // By result, then by year.
+	ran    [][]bool      // Whether the result ran in the year.
+}
+
+func compare(cs []Compared) comparison {
+	c := comparison{}
+	for _, cr := range cs {
+		for _, year := range historyYears(cr.Result.History) {
+			if !slices.Contains(c.years, year) {
+				c.years = append(c.years, year)
+			}
+		}
+	}
+	slices.Sort(c.years)
+	for _, cr := range cs {
+		c.totals = append(c.totals, totalsByYear(cr.Result, c.years))
+		ran := make([]bool, len(c.years))
+		for _, t := range cr.Result.History {
+			ran[slices.Index(c.years, t.Year)] = true
+		}
+		c.ran = append(c.ran, ran)
+	}
+	return c
+}
+
+// WriteComparisonCSV writes a CSV table to w with a row for each year, labelled with the tax year and ages
+// of the first result which ran in the year, giving the total balance,
+// withdrawals and tax raised of each result and, for the second and later results, their differences from the first.
+// The cells of a result which did not run in a year are left empty.
+func WriteComparisonCSV(w io.Writer, cs []Compared) error {
+	c := compare(cs)
+	header := []string{"Year", "Tax Year", "Ages"}
+	for k, cr := range cs {
+		header = append(header, cr.Name+" Balance", cr.Name+" Withdrawn", cr.Name+" Tax Raised")
+		if k > 0 {
+			header = append(header, cr.Name+" Balance Difference", cr.Name+" Withdrawn Difference", cr.Name+" Tax Raised Difference")
+		}
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i, year := range c.years {
+		label := yearLabel{Year: year}
+		for k := range cs {
+			if c.ran[k][i] {
+				label = yearLabel{year, c.totals[k][i].TaxYear, c.totals[k][i].Ages}
+				break
+			}
+		}
+		row := []string{strconv.Itoa(year), label.TaxYear, FormatAges(label.Ages)}
+		first := c.totals[0][i]
+		for k := range cs {
+			yt := c.totals[k][i]
+			if !c.ran[k][i] {
+				row = append(row, "", "", "")
+				if k > 0 {
+					row = append(row, "", "", "")
+				}
+				continue
+			}
+			row = append(row, itoa(yt.Balance), itoa(yt.Withdrawn), itoa(yt.TaxRaised))
+			if k == 0 {
+				continue
+			}
+			if !c.ran[0][i] {
+				row = append(row, "", "", "")
+				continue
+			}
+			row = append(row, itoa(yt.Balance-first.Balance), itoa(yt.Withdrawn-first.Withdrawn), itoa(yt.TaxRaised-first.TaxRaised))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteComparison writes a summary of the comparison to w: which result has the highest final balance,
+// the least total tax and the most years funded, and the years in which the balance of each result
+// crosses that of the first.
+func WriteComparison(w io.Writer, cs []Compared) error {
+	if len(cs) == 0 {
+		return nil
+	}
+	c := compare(cs)
+	finalBalance := make([]int64, len(cs))
+	totalTax := make([]int64, len(cs))
+	yearsFunded := make([]int64, len(cs))
+	for k, cr := range cs {
+		finalBalance[k] = cr.Result.Summary().FinalBalance
+		for i := range c.years {
+			totalTax[k] += c.totals[k][i].TaxRaised
+			if c.ran[k][i] && c.totals[k][i].Shortfall == 0 {
+				yearsFunded[k]++
+			}
+		}
+	}
+	lines := []string{
+		winners("Highest final balance", cs, finalBalance, slices.Max(finalBalance)),
+		winners("Least total tax", cs, totalTax, slices.Min(totalTax)),
+		winners("Most years funded", cs, yearsFunded, slices.Max(yearsFunded)),
+	}
+	for k := 1; k < len(cs); k++ {
+		lines = append(lines, crossings(c, cs, k))
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// winners names the results whose value is best, along with the value of each result.
+func winners(title string, cs []Compared, values []int64, best int64) string {
+	names, all := []string{}, []string{}
+	for k, cr := range cs {
+		if values[k] == best {
+			names = append(names, cr.Name)
+		}
+		all = append(all, fmt.Sprintf("%s %s", cr.Name, formatAmount(values[k])))
+	}
+	return fmt.Sprintf("%s: %s (%s)", title, strings.Join(names, " and "), strings.Join(all, ", "))
+}
+
+// crossings describes the years in which the balance of the k'th result crosses that of the first:
+// the years in which the one that is ahead changes.
+func crossings(c comparison, cs []Compared, k int) string {
+	years := []string{}
+	sign := 0
+	for i, year := range c.years {
+		if !c.ran[0][i] || !c.ran[k][i] {
+			continue
+		}
+		d := c.totals[k][i].Balance - c.totals[0][i].Balance
+		s := 0
+		switch {
+		case d > 0:
+			s = 1
+		case d < 0:
+			s = -1
+		}
+		if s != 0 && sign != 0 && s != sign {
+			years = append(years, strconv.Itoa(year))
+		}
+		if s != 0 {
+			sign = s
+		}
+	}
+	if len(years) == 0 {
+		return fmt.Sprintf("The balances of %s and %s do not cross", cs[0].Name, cs[k].Name)
+	}
+	plural := ""
+	if len(years) > 1 {
+		plural = "s"
+	}
+	return fmt.Sprintf("The balances of %s and %s cross in year%s %s", cs[0].Name, cs[k].Name, plural, strings.Join(years, ", "))
+}
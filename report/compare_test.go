@@ -0,0 +1,67 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// testComparison compares testResult with a result of three years, from a single savings account,
+// which starts behind and ends ahead and runs a year longer, for a person aged 70 in year 1.
+func testComparison() []Compared {
+	other := drawdown.DrawResult{}
+	for year, balance := range []int64{500000, 1000, 2000} {
+		other.History = append(other.History, drawdown.Transaction{
+			Year: year + 1, TaxYear: []string{"2025/26", "2026/27", "2027/28"}[year], Ages: []int{70 + year},
+			Source: "Savings", Kind: drawdown.Liquid, Amount: 20000, Balance: balance, PriceIndex: 1,
+		})
+	}
+	return []Compared{{Name: "A", Result: testResult()}, {Name: "B", Result: other}}
+}
+
+func TestWriteComparisonCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteComparisonCSV(&b, testComparison()); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		// Each year is labelled from the first result which ran in it.
+		{"Year", "Tax Year", "Ages", "A Balance", "A Withdrawn", "A Tax Raised", "B Balance", "B Withdrawn", "B Tax Raised",
+			"B Balance Difference", "B Withdrawn Difference", "B Tax Raised Difference"},
+		{"1", "2025/26", "67/63", "510000", "30000", "1486", "500000", "20000", "0", "-10000", "-10000", "-1486"},
+		{"2", "2026/27", "68/64", "0", "31000", "0", "1000", "20000", "0", "1000", "-11000", "0"},
+		{"3", "2027/28", "72", "", "", "", "2000", "20000", "0", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got %q, want %q", records, want)
+	}
+}
+
+func TestWriteComparison(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteComparison(&b, testComparison()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Highest final balance: B (A 0, B 2,000)",
+		"Least total tax: B (A 1,486, B 0)",
+		"Most years funded: B (A 1, B 3)",
+		"The balances of A and B cross in year 2",
+	}
+	if got := strings.Split(strings.TrimRight(b.String(), "\n"), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	b.Reset()
+	if err := WriteComparison(&b, nil); err != nil || b.Len() != 0 {
+		t.Errorf("got %q, %v for no results, want nothing", b.String(), err)
+	}
+}
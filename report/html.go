@@ -0,0 +1,290 @@
+// Package report writes the results of drawdown iterations in formats other than the plain CSV of the command.
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+var page = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; }
+td { text-align: right; }
+th { text-align: left; background: #f4f4f4; }
+figure { margin: 0 0 2em 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Summary}}<table>
+{{range .}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+{{range .Charts}}<figure>{{.}}</figure>
+{{end}}
+</body>
+</html>
+`))
+
+type summaryLine struct {
+	Name  string
+	Value string
+}
+
+type pageData struct {
+	Title   string
+	Summary []summaryLine
+	Charts  []template.HTML
+}
+
+// WriteHTML writes a self-contained HTML report of the result to w.
+// The report contains the summary figures and charts of the balance and withdrawals by source,
+// the tax raised, and the spending against the need in each year.
+func WriteHTML(w io.Writer, title string, r drawdown.DrawResult) error {
+	s := r.Summary()
+	data := pageData{
+		Title: title,
+		Summary: []summaryLine{
+			{"Total withdrawn", formatAmount(s.TotalWithdrawn)},
+			{"Total tax raised", formatAmount(s.TotalTaxRaised)},
+			{"Final balance", formatAmount(s.FinalBalance)},
+			{"Final balance in today's money", formatAmount(s.RealFinalBalance)},
+			{"Final estate", formatAmount(s.FinalEstate)},
+			{"Final year", fmt.Sprint(s.FinalYear)},
+			{"Shortfall years", fmt.Sprint(s.ShortfallYears)},
+			{"First shortfall year", fmt.Sprint(s.FirstShortfallYear)},
+			{"Total shortfall", formatAmount(s.TotalShortfall)},
+		},
+	}
+
+	years := historyYears(r.History)
+	balances := bySource(r.History, years, func(t drawdown.Transaction) (int64, bool) {
+		return t.Balance, t.Kind == drawdown.Liquid
+	})
+	withdrawals := bySource(r.History, years, func(t drawdown.Transaction) (int64, bool) {
+		return t.Amount, t.Kind == drawdown.Liquid || t.Kind == drawdown.Credit
+	})
+	tax := make([]int64, len(years))
+	for _, t := range r.History {
+		tax[slices.Index(years, t.Year)] += t.TaxRaised
+	}
+	need := yearlyNeed(r.Ledger, years)
+	spending := slices.Clone(need)
+	for _, sf := range r.Shortfalls() {
+		if i := slices.Index(years, sf.Year); i >= 0 {
+			spending[i] -= sf.Amount
+		}
+	}
+
+	labels := yearLabels(r.History, years)
+	data.Charts = []template.HTML{
+		stackedBars("Balance by source", labels, balances),
+		stackedBars("Withdrawals by source", labels, withdrawals),
+		stackedBars("Tax raised", labels, []series{{"Tax raised", tax}}),
+		lines("Spending and need", labels, []series{{"Need", need}, {"Spending", spending}}),
+	}
+	return page.Execute(w, data)
+}
+
+// SweepRow is the outcome of one combination of rates in a sweep.
+type SweepRow struct {
+	Rates   drawdown.DrawRates
+	Summary drawdown.DrawSummary
+}
+
+// RateAxes are the short names by which the rates of a sweep can be chosen as heatmap axes,
+// as used to label rows in summary.csv.
+var RateAxes = map[string]struct {
+	Label string
+	Rate  func(drawdown.DrawRates) float64
+}{
+	"igr": {"Investment Growth Rate", func(r drawdown.DrawRates) float64 { return r.InvestmentGrowthRate }},
+	"sgr": {"Savings Growth Rate", func(r drawdown.DrawRates) float64 { return r.SavingsGrowthRate }},
+	"air": {"Annual Inflation Rate", func(r drawdown.DrawRates) float64 { return r.AnnualInflationRate }},
+	"pcr": {"Platform Charge Rate", func(r drawdown.DrawRates) float64 { return r.PlatformChargeRate }},
+	"tbi": {"Tax Band Annual Percentage Increase", func(r drawdown.DrawRates) float64 { return r.TaxBandAnnualPctIncrease }},
+	"hpg": {"House Price Growth Rate", func(r drawdown.DrawRates) float64 { return r.HousePriceGrowthRate }},
+	"cci": {"Care Cost Inflation Rate", func(r drawdown.DrawRates) float64 { return r.CareCostInflationRate }},
+}
+
+// WriteSweepHTML writes a self-contained HTML report of a sweep to w,
+// with a heatmap of the final balance across the two given rate axes (see RateAxes).
+// Each cell shows the mean final balance over the values of the other rates.
+func WriteSweepHTML(w io.Writer, title string, rows []SweepRow, xAxis string, yAxis string) error {
+	x, ok := RateAxes[xAxis]
+	if !ok {
+		return fmt.Errorf("unknown rate axis %q", xAxis)
+	}
+	y, ok := RateAxes[yAxis]
+	if !ok {
+		return fmt.Errorf("unknown rate axis %q", yAxis)
+	}
+	xs, ys := []float64{}, []float64{}
+	for _, row := range rows {
+		if xv := x.Rate(row.Rates); !slices.Contains(xs, xv) {
+			xs = append(xs, xv)
+		}
+		if yv := y.Rate(row.Rates); !slices.Contains(ys, yv) {
+			ys = append(ys, yv)
+		}
+	}
+	slices.Sort(xs)
+	slices.Sort(ys)
+	totals := make([][]int64, len(ys))
+	counts := make([][]int64, len(ys))
+	for j := range ys {
+		totals[j] = make([]int64, len(xs))
+		counts[j] = make([]int64, len(xs))
+	}
+	for _, row := range rows {
+		i, j := slices.Index(xs, x.Rate(row.Rates)), slices.Index(ys, y.Rate(row.Rates))
+		totals[j][i] += row.Summary.FinalBalance
+		counts[j][i]++
+	}
+	for j := range ys {
+		for i := range xs {
+			if counts[j][i] > 0 {
+				totals[j][i] /= counts[j][i]
+			}
+		}
+	}
+	data := pageData{
+		Title: title,
+		Summary: []summaryLine{
+			{"Combinations of rates", fmt.Sprint(len(rows))},
+		},
+		Charts: []template.HTML{
+			heatmap("Mean final balance", x.Label, xs, y.Label, ys, totals),
+		},
+	}
+	return page.Execute(w, data)
+}
+
+// historyYears returns the distinct years of the history in increasing order.
+func historyYears(h drawdown.DrawHistory) []int {
+	years := []int{}
+	for _, t := range h {
+		if !slices.Contains(years, t.Year) {
+			years = append(years, t.Year)
+		}
+	}
+	slices.Sort(years)
+	return years
+}
+
+// yearLabel identifies a year by its number and, if the scenario has a calendar, its tax year and ages.
+type yearLabel struct {
+	Year    int
+	TaxYear string
+	Ages    []int
+}
+
+// yearLabels returns the labels of each of the years of the history.
+func yearLabels(h drawdown.DrawHistory, years []int) []yearLabel {
+	labels := make([]yearLabel, len(years))
+	for i, year := range years {
+		labels[i].Year = year
+	}
+	for _, t := range h {
+		l := &labels[slices.Index(years, t.Year)]
+		l.TaxYear, l.Ages = t.TaxYear, t.Ages
+	}
+	return labels
+}
+
+// axis returns the label of the year on the axis of a chart: its tax year, or else its number.
+func (l yearLabel) axis() string {
+	if l.TaxYear != "" {
+		return l.TaxYear
+	}
+	return fmt.Sprint(l.Year)
+}
+
+// String describes the year in full, such as "year 1 (2025/26, ages 67/63)".
+func (l yearLabel) String() string {
+	s := fmt.Sprintf("year %d", l.Year)
+	if l.TaxYear == "" {
+		return s
+	}
+	if len(l.Ages) == 0 {
+		return fmt.Sprintf("%s (%s)", s, l.TaxYear)
+	}
+	return fmt.Sprintf("%s (%s, ages %s)", s, l.TaxYear, FormatAges(l.Ages))
+}
+
+// bySource returns a series for each source with the value chosen from each of its transactions.
+// Transactions for which include is false are left out.
+func bySource(h drawdown.DrawHistory, years []int, value func(t drawdown.Transaction) (v int64, include bool)) []series {
+	ss := []series{}
+	index := map[string]int{}
+	for _, t := range h {
+		v, include := value(t)
+		if !include {
+			continue
+		}
+		k, ok := index[t.Source]
+		if !ok {
+			k = len(ss)
+			index[t.Source] = k
+			ss = append(ss, series{Name: t.Source, Values: make([]int64, len(years))})
+		}
+		ss[k].Values[slices.Index(years, t.Year)] += v
+	}
+	return ss
+}
+
+// yearTotal is the sum over the sources of a year's transactions.
+type yearTotal struct {
+	TaxYear   string
+	Ages      []int
+	Need      int64
+	Withdrawn int64
+	TaxRaised int64
+	TaxPaid   int64
+	Balance   int64 // The balance of the liquid sources.
+	Shortfall int64
+}
+
+// totalsByYear returns the totals of the result for each of the years.
+func totalsByYear(r drawdown.DrawResult, years []int) []yearTotal {
+	totals := make([]yearTotal, len(years))
+	for i, need := range yearlyNeed(r.Ledger, years) {
+		totals[i].Need = need
+	}
+	for _, t := range r.History {
+		yt := &totals[slices.Index(years, t.Year)]
+		yt.TaxYear, yt.Ages = t.TaxYear, t.Ages
+		yt.Withdrawn += t.Amount
+		yt.TaxRaised += t.TaxRaised
+		yt.TaxPaid += t.Tax
+		if t.Kind == drawdown.Liquid {
+			yt.Balance += t.Balance
+		}
+	}
+	for _, sf := range r.Shortfalls() {
+		if i := slices.Index(years, sf.Year); i >= 0 {
+			totals[i].Shortfall += sf.Amount
+		}
+	}
+	return totals
+}
+
+// FormatAges returns the ages separated by slashes, such as "68/64".
+func FormatAges(ages []int) string {
+	s := make([]string, len(ages))
+	for i, age := range ages {
+		s[i] = strconv.Itoa(age)
+	}
+	return strings.Join(s, "/")
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// svgs returns the SVG images embedded in an HTML report.
+func svgs(html string) []string {
+	return regexp.MustCompile(`(?s)<svg .*?</svg>`).FindAllString(html, -1)
+}
+
+// checkWellFormed fails the test if the text is not well-formed XML.
+func checkWellFormed(t *testing.T, name string, text string) {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(text))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			return
+		} else if err != nil {
+			t.Errorf("%s is not well-formed: %v", name, err)
+			return
+		}
+	}
+}
+
+// testResult returns a result of two years with a savings account, a pension and a home,
+// in which tax is raised on the pension and the need is not met in the second year.
+func testResult() drawdown.DrawResult {
+	transaction := func(year int, source string, kind drawdown.SourceKind, amount, taxRaised, balance int64) drawdown.Transaction {
+		return drawdown.Transaction{
+			Year: year, TaxYear: []string{"", "2025/26", "2026/27"}[year], Ages: []int{66 + year, 62 + year},
+			Source: source, Kind: kind, Amount: amount, TaxRaised: taxRaised, Balance: balance, PriceIndex: 1,
+		}
+	}
+	withTax := func(t drawdown.Transaction, tax int64) drawdown.Transaction {
+		t.Tax = tax
+		return t
+	}
+	return drawdown.DrawResult{
+		History: drawdown.DrawHistory{
+			transaction(1, "Savings", drawdown.Liquid, 10000, 0, 30000),
+			transaction(1, "Pension", drawdown.Liquid, 20000, 1486, 480000),
+			transaction(1, "Home", drawdown.Illiquid, 0, 0, 400000),
+			// The tax raised in year 1 is paid from savings in year 2.
+			withTax(transaction(2, "Savings", drawdown.Liquid, 30000, 0, 0), 1486),
+			transaction(2, "Pension", drawdown.Liquid, 1000, 0, 0),
+			transaction(2, "Home", drawdown.Illiquid, 0, 0, 410000),
+		},
+		Events: []drawdown.Event{drawdown.Shortfall{Year: 2, Amount: 2486}},
+		Ledger: drawdown.Ledger{
+			{Year: 1, Kind: drawdown.NeedEntry, Amount: 30000},
+			{Year: 2, Kind: drawdown.NeedEntry, Amount: 32000},
+		},
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteHTML(&b, "Test <report>", testResult()); err != nil {
+		t.Fatal(err)
+	}
+	html := b.String()
+	for _, want := range []string{
+		"<title>Test &lt;report&gt;</title>",
+		"<tr><th>Total withdrawn</th><td>" + formatAmount(61000) + "</td></tr>",
+		"<tr><th>Total tax raised</th><td>" + formatAmount(1486) + "</td></tr>",
+		"<tr><th>Total shortfall</th><td>" + formatAmount(2486) + "</td></tr>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("the report does not contain %q", want)
+		}
+	}
+	charts := svgs(html)
+	if len(charts) != 4 {
+		t.Fatalf("got %d charts, want 4", len(charts))
+	}
+	for i, chart := range charts {
+		checkWellFormed(t, fmt.Sprintf("chart %d", i+1), chart)
+	}
+	// The balances of the liquid sources are stacked; the home is left out.
+	if !strings.Contains(charts[0], "<title>Pension, year 1 (2025/26, ages 67/63): "+formatAmount(480000)+"</title>") || strings.Contains(charts[0], "Home") {
+		t.Error("the balance chart does not show the balance of each liquid source")
+	}
+	if !strings.Contains(charts[2], "<title>Tax raised, year 1 (2025/26, ages 67/63): "+formatAmount(1486)+"</title>") {
+		t.Error("the tax chart does not show the tax raised in year 1")
+	}
+	for i, chart := range charts {
+		if !strings.Contains(chart, `font-weight="normal">2026/27</text>`) {
+			t.Errorf("chart %d does not label its axis with the tax years", i+1)
+		}
+	}
+}
+
+func TestWriteSweepHTML(t *testing.T) {
+	rows := []SweepRow{}
+	for _, igr := range []float64{1, 2, 3} {
+		for _, sgr := range []float64{1, 2} {
+			for _, air := range []float64{2, 3} {
+				rates := drawdown.DrawRates{InvestmentGrowthRate: igr, SavingsGrowthRate: sgr, AnnualInflationRate: air}
+				rows = append(rows, SweepRow{Rates: rates, Summary: drawdown.DrawSummary{FinalBalance: int64(1000*igr + 100*sgr + 10*air)}})
+			}
+		}
+	}
+	var b bytes.Buffer
+	if err := WriteSweepHTML(&b, "Sweep", rows, "igr", "sgr"); err != nil {
+		t.Fatal(err)
+	}
+	charts := svgs(b.String())
+	if len(charts) != 1 {
+		t.Fatalf("got %d charts, want a heatmap", len(charts))
+	}
+	checkWellFormed(t, "the heatmap", charts[0])
+	if cells := strings.Count(charts[0], "<rect "); cells != 6 {
+		t.Errorf("got %d cells, want one for each of 3 by 2 values", cells)
+	}
+	// Each cell is the mean over the values of the inflation rate.
+	if want := "Investment Growth Rate 3.00, Savings Growth Rate 2.00: " + formatAmount(3225); !strings.Contains(charts[0], want) {
+		t.Errorf("the heatmap has no cell %q", want)
+	}
+
+	if err := WriteSweepHTML(io.Discard, "Sweep", rows, "igr", "xyz"); err == nil {
+		t.Error("an unknown axis was accepted")
+	}
+}
+
+func TestNiceCeiling(t *testing.T) {
+	for _, tt := range []struct{ v, want int64 }{
+		{1, 1}, {3, 5}, {7, 10}, {10, 10}, {11, 20}, {480001, 500000}, {2000000, 2000000},
+	} {
+		if got := niceCeiling(tt.v); got != tt.want {
+			t.Errorf("niceCeiling(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
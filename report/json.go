@@ -0,0 +1,109 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// Inputs are the parameters with which a scenario was run.
+type Inputs struct {
+	Scenario               string
+	Years                  int
+	Year1AnnualIncome      int
+	Rates                  drawdown.DrawRates
+	Monthly                bool  // The scenario was simulated month by month.
+	ContinueAfterShortfall bool  // The run continued after a year in which the need could not be met.
+	Seed                   int64 // The seed of random events, such as the start of care.
+	Real                   bool  // The amounts are in today's (year 1) money rather than nominal.
+}
+
+// eventRecord is the JSON form of an Event.
+type eventRecord struct {
+	Year   int
+	Kind   string // The type of the event, such as "Shortfall".
+	Amount int64  // The amount of the shortfall or tax.
+	Event  string
+}
+
+func eventRecords(events []drawdown.Event) []eventRecord {
+	records := []eventRecord{}
+	for _, e := range events {
+		amount := int64(0)
+		switch e := e.(type) {
+		case drawdown.Shortfall:
+			amount = e.Amount
+		case drawdown.UnpaidTax:
+			amount = e.Amount
+		case drawdown.TaxDueAfterHorizon:
+			amount = e.Amount
+		}
+		records = append(records, eventRecord{e.EventYear(), reflect.TypeOf(e).Name(), amount, e.String()})
+	}
+	return records
+}
+
+// WriteJSON writes the result to w as a single JSON document holding the inputs,
+// the summary, the events and the transactions.
+func WriteJSON(w io.Writer, in Inputs, r drawdown.DrawResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Inputs  Inputs
+		Summary drawdown.DrawSummary
+		Events  []eventRecord
+		History drawdown.DrawHistory
+	}{in, r.Summary(), eventRecords(r.Events), r.History})
+}
+
+// WriteNDJSON writes the result to w as newline delimited JSON.
+// The first line holds the inputs, each transaction follows on a line of its own,
+// then each event, and the last line holds the summary.
+// Every line is an object with a single key, naming what it holds.
+func WriteNDJSON(w io.Writer, in Inputs, r drawdown.DrawResult) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(struct{ Inputs Inputs }{in}); err != nil {
+		return err
+	}
+	for _, t := range r.History {
+		if err := enc.Encode(struct{ Transaction drawdown.Transaction }{t}); err != nil {
+			return err
+		}
+	}
+	for _, e := range eventRecords(r.Events) {
+		if err := enc.Encode(struct{ Event eventRecord }{e}); err != nil {
+			return err
+		}
+	}
+	return enc.Encode(struct{ Summary drawdown.DrawSummary }{r.Summary()})
+}
+
+// WriteSweepJSON writes the rows of a sweep to w as a single JSON document.
+// The rates of the inputs are cleared since each row holds its own.
+func WriteSweepJSON(w io.Writer, in Inputs, rows []SweepRow) error {
+	in.Rates = drawdown.DrawRates{}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Inputs Inputs
+		Rows   []SweepRow
+	}{in, rows})
+}
+
+// WriteSweepNDJSON writes the rows of a sweep to w as newline delimited JSON,
+// with the inputs on the first line and a row on each following line.
+func WriteSweepNDJSON(w io.Writer, in Inputs, rows []SweepRow) error {
+	in.Rates = drawdown.DrawRates{}
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(struct{ Inputs Inputs }{in}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := enc.Encode(struct{ Row SweepRow }{row}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	in := Inputs{Scenario: "Test", Years: 2, Year1AnnualIncome: 30000, Monthly: true, ContinueAfterShortfall: true, Seed: 7, Real: true}
+	if err := WriteJSON(&b, in, testResult()); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Inputs  Inputs
+		Summary drawdown.DrawSummary
+		Events  []eventRecord
+		History []json.RawMessage // A transaction's Kind is written as text, which cannot be read back.
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Inputs != in {
+		t.Errorf("inputs %+v, want %+v", got.Inputs, in)
+	}
+	if got.Summary.TotalTaxRaised != 1486 || got.Summary.TotalShortfall != 2486 || len(got.History) != 6 {
+		t.Errorf("got summary %+v and %d transactions", got.Summary, len(got.History))
+	}
+	want := eventRecord{Year: 2, Kind: "Shortfall", Amount: 2486, Event: "year 2: not enough funds, need 2486 unmet"}
+	if len(got.Events) != 1 || got.Events[0] != want {
+		t.Errorf("events %+v, want %+v", got.Events, want)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteNDJSON(&b, Inputs{Scenario: "Test"}, testResult()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	// The inputs, 6 transactions, an event and the summary.
+	wantKeys := []string{"Inputs", "Transaction", "Transaction", "Transaction", "Transaction", "Transaction", "Transaction", "Event", "Summary"}
+	if len(lines) != len(wantKeys) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(wantKeys))
+	}
+	for i, line := range lines {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &object); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if _, ok := object[wantKeys[i]]; !ok || len(object) != 1 {
+			t.Errorf("line %d: got %s, want an object with the single key %s", i+1, line, wantKeys[i])
+		}
+	}
+	var event struct{ Event eventRecord }
+	if err := json.Unmarshal([]byte(lines[7]), &event); err != nil || event.Event.Amount != 2486 {
+		t.Errorf("got event %s, want the amount of the shortfall", lines[7])
+	}
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// WriteMonteCarlo writes a readable summary of a set of Monte Carlo runs to w.
+func WriteMonteCarlo(w io.Writer, in Inputs, mc drawdown.MonteCarlo, s drawdown.MonteCarloSummary) error {
+	percentiles := func(p drawdown.Percentiles) string {
+		return fmt.Sprintf("10th percentile %s, median %s, 90th percentile %s", formatAmount(p.P10), formatAmount(p.P50), formatAmount(p.P90))
+	}
+	_, err := fmt.Fprintf(w, "%s: %d runs of %d years with seed %d\n"+
+		"Need met in every year: %d runs (%.1f%%)\n"+
+		"Final balance: %s\n"+
+		"Final balance in today's money: %s\n"+
+		"Total shortfall: %s\n",
+		in.Scenario, s.Runs, in.Years, mc.Seed,
+		s.RunsWithoutShortfall, s.SuccessPct,
+		percentiles(s.FinalBalance),
+		percentiles(s.RealFinalBalance),
+		percentiles(s.TotalShortfall))
+	return err
+}
+
+// WriteMonteCarloJSON writes a set of Monte Carlo runs to w as a single JSON document
+// holding the inputs, the distributions of the rates, the summary and a row for each run.
+// The rates of the inputs are cleared since each row holds its own.
+func WriteMonteCarloJSON(w io.Writer, in Inputs, mc drawdown.MonteCarlo, s drawdown.MonteCarloSummary, rows []SweepRow) error {
+	in.Rates = drawdown.DrawRates{}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Inputs     Inputs
+		MonteCarlo drawdown.MonteCarlo
+		Summary    drawdown.MonteCarloSummary
+		Rows       []SweepRow
+	}{in, mc, s, rows})
+}
+
+// WriteMonteCarloNDJSON writes a set of Monte Carlo runs to w as newline delimited JSON,
+// with the inputs and the distributions of the rates on the first line, a row on each following line,
+// and the summary on the last line.
+func WriteMonteCarloNDJSON(w io.Writer, in Inputs, mc drawdown.MonteCarlo, s drawdown.MonteCarloSummary, rows []SweepRow) error {
+	in.Rates = drawdown.DrawRates{}
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(struct {
+		Inputs     Inputs
+		MonteCarlo drawdown.MonteCarlo
+	}{in, mc}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := enc.Encode(struct{ Row SweepRow }{row}); err != nil {
+			return err
+		}
+	}
+	return enc.Encode(struct{ Summary drawdown.MonteCarloSummary }{s})
+}
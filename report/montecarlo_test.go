@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// testMonteCarlo returns the inputs, distributions, summary and rows of two runs, one of which falls short.
+func testMonteCarlo() (Inputs, drawdown.MonteCarlo, drawdown.MonteCarloSummary, []SweepRow) {
+	in := Inputs{Scenario: "Test", Years: 30, Year1AnnualIncome: 35000, Rates: drawdown.DrawRates{InvestmentGrowthRate: 4}}
+	mc := drawdown.MonteCarlo{Mean: in.Rates, StdDev: drawdown.DrawRates{InvestmentGrowthRate: 1}, Runs: 2, Seed: 7}
+	rows := []SweepRow{
+		{Rates: drawdown.DrawRates{InvestmentGrowthRate: 4.5}, Summary: drawdown.DrawSummary{FinalBalance: 120000, FinalYear: 30}},
+		{Rates: drawdown.DrawRates{InvestmentGrowthRate: 3.2}, Summary: drawdown.DrawSummary{FinalYear: 30, ShortfallYears: 2, TotalShortfall: 50000}},
+	}
+	summaries := []drawdown.DrawSummary{rows[0].Summary, rows[1].Summary}
+	return in, mc, drawdown.SummarizeMonteCarlo(summaries), rows
+}
+
+func TestWriteMonteCarlo(t *testing.T) {
+	in, mc, s, _ := testMonteCarlo()
+	s.FinalBalance = drawdown.Percentiles{P10: 1000, P50: 60000, P90: 1200000}
+	var b bytes.Buffer
+	if err := WriteMonteCarlo(&b, in, mc, s); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Test: 2 runs of 30 years with seed 7",
+		"Need met in every year: 1 runs (50.0%)",
+		"Final balance: 10th percentile 1,000, median 60,000, 90th percentile 1,200,000",
+	}
+	lines := strings.Split(b.String(), "\n")
+	if len(lines) < len(want) || !reflect.DeepEqual(lines[:len(want)], want) {
+		t.Errorf("got %q, want it to start %q", lines, want)
+	}
+}
+
+func TestWriteMonteCarloJSON(t *testing.T) {
+	in, mc, s, rows := testMonteCarlo()
+	var b bytes.Buffer
+	if err := WriteMonteCarloJSON(&b, in, mc, s, rows); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Inputs     Inputs
+		MonteCarlo drawdown.MonteCarlo
+		Summary    drawdown.MonteCarloSummary
+		Rows       []SweepRow
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	wantInputs := in
+	wantInputs.Rates = drawdown.DrawRates{} // Each row holds its own rates.
+	if got.Inputs != wantInputs {
+		t.Errorf("inputs %+v, want %+v", got.Inputs, wantInputs)
+	}
+	if got.MonteCarlo != mc || got.Summary != s || !reflect.DeepEqual(got.Rows, rows) {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestWriteMonteCarloNDJSON(t *testing.T) {
+	in, mc, s, rows := testMonteCarlo()
+	var b bytes.Buffer
+	if err := WriteMonteCarloNDJSON(&b, in, mc, s, rows); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	wantKeys := [][]string{{"Inputs", "MonteCarlo"}, {"Row"}, {"Row"}, {"Summary"}}
+	if len(lines) != len(wantKeys) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(wantKeys))
+	}
+	for i, line := range lines {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		keys := []string{}
+		for k := range m {
+			keys = append(keys, k)
+		}
+		if len(keys) != len(wantKeys[i]) {
+			t.Errorf("line %d has keys %v, want %v", i+1, keys, wantKeys[i])
+			continue
+		}
+		for _, k := range wantKeys[i] {
+			if _, ok := m[k]; !ok {
+				t.Errorf("line %d has keys %v, want %v", i+1, keys, wantKeys[i])
+			}
+		}
+	}
+}
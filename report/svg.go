@@ -0,0 +1,251 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+)
+
+// palette is the sequence of colours given to the series of a chart.
+var palette = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+const (
+	chartWidth   = 800
+	chartHeight  = 320
+	marginLeft   = 80
+	marginRight  = 160 // Room for the legend.
+	marginTop    = 30
+	marginBottom = 40
+	plotWidth    = chartWidth - marginLeft - marginRight
+	plotHeight   = chartHeight - marginTop - marginBottom
+)
+
+// series is a named sequence of values, one for each year of a chart.
+type series struct {
+	Name   string
+	Values []int64
+}
+
+// svg accumulates the elements of an SVG image.
+type svg struct {
+	b strings.Builder
+}
+
+func newSVG(width, height int, title string) *svg {
+	s := &svg{}
+	fmt.Fprintf(&s.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`, width, height, width, height)
+	s.text(float64(width)/2, 18, "middle", "bold", title)
+	return s
+}
+
+func (s *svg) text(x, y float64, anchor string, weight string, text string) {
+	fmt.Fprintf(&s.b, `<text x="%.1f" y="%.1f" text-anchor="%s" font-weight="%s">%s</text>`, x, y, anchor, weight, template.HTMLEscapeString(text))
+}
+
+func (s *svg) rect(x, y, w, h float64, fill string, tip string) {
+	fmt.Fprintf(&s.b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s</title></rect>`, x, y, w, h, fill, template.HTMLEscapeString(tip))
+}
+
+func (s *svg) line(x1, y1, x2, y2 float64, stroke string) {
+	fmt.Fprintf(&s.b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`, x1, y1, x2, y2, stroke)
+}
+
+func (s *svg) polyline(points []string, stroke string) {
+	fmt.Fprintf(&s.b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(points, " "), stroke)
+}
+
+func (s *svg) html() template.HTML {
+	return template.HTML(s.b.String() + "</svg>")
+}
+
+// legend draws a key to the series to the right of the plot.
+func (s *svg) legend(names []string) {
+	for i, name := range names {
+		y := float64(marginTop + 14*i)
+		s.rect(marginLeft+plotWidth+12, y, 10, 10, palette[i%len(palette)], name)
+		s.text(marginLeft+plotWidth+26, y+9, "start", "normal", name)
+	}
+}
+
+// axes draws the axes with the years, labelled with their tax years if known, along the bottom
+// and a scale up to maxValue on the left. It returns the function mapping a value to a y coordinate.
+func (s *svg) axes(years []yearLabel, maxValue int64) func(int64) float64 {
+	if maxValue <= 0 {
+		maxValue = 1
+	}
+	top := niceCeiling(maxValue)
+	y := func(v int64) float64 {
+		return marginTop + plotHeight - float64(v)/float64(top)*plotHeight
+	}
+	s.line(marginLeft, marginTop, marginLeft, marginTop+plotHeight, "#333")
+	s.line(marginLeft, marginTop+plotHeight, marginLeft+plotWidth, marginTop+plotHeight, "#333")
+	const ticks = 5
+	for i := 0; i <= ticks; i++ {
+		v := top * int64(i) / ticks
+		s.line(marginLeft-4, y(v), marginLeft+plotWidth, y(v), "#ddd")
+		s.text(marginLeft-6, y(v)+4, "end", "normal", formatAmount(v))
+	}
+	step := 1
+	if len(years) > 0 {
+		fit := max(1, plotWidth/(7*len(years[0].axis())+12)) // The number of labels which fit along the axis.
+		step = (len(years) + fit - 1) / fit
+	}
+	for i, year := range years {
+		if i%step != 0 {
+			continue
+		}
+		s.text(xCentre(i, len(years)), marginTop+plotHeight+16, "middle", "normal", year.axis())
+	}
+	return y
+}
+
+// xCentre returns the x coordinate of the centre of the i'th of n slots along the x axis.
+func xCentre(i, n int) float64 {
+	return marginLeft + (float64(i)+0.5)*plotWidth/float64(n)
+}
+
+// stackedBars returns a chart with a bar for each year made up of the values of each series.
+func stackedBars(title string, years []yearLabel, ss []series) template.HTML {
+	s := newSVG(chartWidth, chartHeight, title)
+	totals := make([]int64, len(years))
+	for _, sr := range ss {
+		for i, v := range sr.Values {
+			totals[i] += max(0, v)
+		}
+	}
+	maxTotal := int64(0)
+	for _, t := range totals {
+		maxTotal = max(maxTotal, t)
+	}
+	y := s.axes(years, maxTotal)
+	barWidth := plotWidth / float64(max(1, len(years))) * 0.8
+	for i, year := range years {
+		base := int64(0)
+		for j, sr := range ss {
+			v := max(0, sr.Values[i])
+			if v == 0 {
+				continue
+			}
+			tip := fmt.Sprintf("%s, %s: %s", sr.Name, year, formatAmount(v))
+			s.rect(xCentre(i, len(years))-barWidth/2, y(base+v), barWidth, y(base)-y(base+v), palette[j%len(palette)], tip)
+			base += v
+		}
+	}
+	names := []string{}
+	for _, sr := range ss {
+		names = append(names, sr.Name)
+	}
+	s.legend(names)
+	return s.html()
+}
+
+// lines returns a chart with a line for each series.
+func lines(title string, years []yearLabel, ss []series) template.HTML {
+	s := newSVG(chartWidth, chartHeight, title)
+	maxValue := int64(0)
+	for _, sr := range ss {
+		for _, v := range sr.Values {
+			maxValue = max(maxValue, v)
+		}
+	}
+	y := s.axes(years, maxValue)
+	names := []string{}
+	for j, sr := range ss {
+		points := []string{}
+		for i, v := range sr.Values {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", xCentre(i, len(years)), y(max(0, v))))
+		}
+		s.polyline(points, palette[j%len(palette)])
+		names = append(names, sr.Name)
+	}
+	s.legend(names)
+	return s.html()
+}
+
+// heatmap returns a grid of cells coloured from red (lowest) to green (highest) value.
+func heatmap(title string, xLabel string, xs []float64, yLabel string, ys []float64, values [][]int64) template.HTML {
+	const cell = 56
+	width := marginLeft + cell*len(xs) + 20
+	height := marginTop + cell*len(ys) + marginBottom + 10
+	s := newSVG(width, height, title)
+	lo, hi := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, row := range values {
+		for _, v := range row {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	for j, yv := range ys {
+		top := float64(marginTop + cell*(len(ys)-1-j))
+		s.text(marginLeft-6, top+cell/2+4, "end", "normal", fmt.Sprintf("%.2f", yv))
+		for i, xv := range xs {
+			v := values[j][i]
+			left := float64(marginLeft + cell*i)
+			tip := fmt.Sprintf("%s %.2f, %s %.2f: %s", xLabel, xv, yLabel, yv, formatAmount(v))
+			s.rect(left, top, cell-1, cell-1, heatColour(v, lo, hi), tip)
+			s.text(left+cell/2, top+cell/2+4, "middle", "normal", formatShort(v))
+		}
+	}
+	for i, xv := range xs {
+		s.text(float64(marginLeft+cell*i)+cell/2, float64(marginTop+cell*len(ys)+14), "middle", "normal", fmt.Sprintf("%.2f", xv))
+	}
+	s.text(float64(marginLeft+cell*len(xs)/2), float64(marginTop+cell*len(ys)+32), "middle", "bold", xLabel)
+	s.text(12, float64(marginTop+cell*len(ys)/2), "start", "bold", yLabel)
+	return s.html()
+}
+
+// heatColour interpolates between red and green.
+func heatColour(v, lo, hi int64) string {
+	f := 0.5
+	if hi > lo {
+		f = float64(v-lo) / float64(hi-lo)
+	}
+	r := int(225 - 140*f)
+	g := int(90 + 110*f)
+	return fmt.Sprintf("rgb(%d,%d,90)", r, g)
+}
+
+// niceCeiling rounds v up to 1, 2 or 5 times a power of ten.
+func niceCeiling(v int64) int64 {
+	p := int64(1)
+	for p*10 <= v {
+		p *= 10
+	}
+	for _, m := range []int64{1, 2, 5, 10} {
+		if m*p >= v {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// formatAmount formats a whole amount with thousands separators, such as "1,234,567".
+func formatAmount(v int64) string {
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	digits := fmt.Sprint(v)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// formatShort formats an amount in thousands or millions, such as "1.2m" or "350k".
+func formatShort(v int64) string {
+	switch a := math.Abs(float64(v)); {
+	case a >= 1e6:
+		return fmt.Sprintf("%.1fm", float64(v)/1e6)
+	case a >= 1e3:
+		return fmt.Sprintf("%.0fk", float64(v)/1e3)
+	}
+	return fmt.Sprint(v)
+}
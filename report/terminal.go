@@ -0,0 +1,96 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiBold  = "\x1b[1m"
+	ansiReset = "\x1b[0m"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline returns a line of block characters whose heights follow the values.
+func sparkline(values []int64) string {
+	lo, hi := slices.Min(values), slices.Max(values)
+	var b strings.Builder
+	for _, v := range values {
+		k := 0
+		if hi > lo {
+			k = int((v - lo) * int64(len(sparks)-1) / (hi - lo))
+		}
+		b.WriteRune(sparks[k])
+	}
+	return b.String()
+}
+
+// WriteTable writes the result to w as an aligned table with a row for each year,
+// showing the balance of each liquid source and the year's totals, followed by sparklines
+// of the total balance and the amount withdrawn.
+// If colour is set, years with a shortfall are highlighted in red.
+func WriteTable(w io.Writer, r drawdown.DrawResult, colour bool) error {
+	years := historyYears(r.History)
+	if len(years) == 0 {
+		_, err := fmt.Fprintln(w, "No transactions")
+		return err
+	}
+	balances := bySource(r.History, years, func(t drawdown.Transaction) (int64, bool) {
+		return t.Balance, t.Kind == drawdown.Liquid
+	})
+	totals := totalsByYear(r, years)
+	balance := make([]int64, len(years))
+	withdrawn := make([]int64, len(years))
+	for i, yt := range totals {
+		balance[i], withdrawn[i] = yt.Balance, yt.Withdrawn
+	}
+
+	rows := [][]string{{"Year", "Tax Year", "Ages"}}
+	for _, sr := range balances {
+		rows[0] = append(rows[0], sr.Name)
+	}
+	rows[0] = append(rows[0], "Need", "Withdrawn", "Tax Raised", "Balance", "Shortfall")
+	for i, year := range years {
+		yt := totals[i]
+		row := []string{fmt.Sprint(year), yt.TaxYear, FormatAges(yt.Ages)}
+		for _, sr := range balances {
+			row = append(row, formatAmount(sr.Values[i]))
+		}
+		row = append(row, formatAmount(yt.Need), formatAmount(yt.Withdrawn), formatAmount(yt.TaxRaised), formatAmount(yt.Balance), formatAmount(yt.Shortfall))
+		rows = append(rows, row)
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for c, cell := range row {
+			widths[c] = max(widths[c], utf8.RuneCountInString(cell))
+		}
+	}
+
+	for k, row := range rows {
+		cells := make([]string, len(row))
+		for c, cell := range row {
+			cells[c] = fmt.Sprintf("%*s", widths[c], cell)
+		}
+		line := strings.Join(cells, "  ")
+		switch {
+		case !colour:
+		case k == 0:
+			line = ansiBold + line + ansiReset
+		case totals[k-1].Shortfall > 0:
+			line = ansiRed + line + ansiReset
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\nBalance    %s\nWithdrawn  %s\n", sparkline(balance), sparkline(withdrawn))
+	return err
+}
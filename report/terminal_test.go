@@ -0,0 +1,40 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteTable(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteTable(&b, testResult(), false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, want a header, two years, a blank line and two sparklines:\n%s", len(lines), b.String())
+	}
+	if got, want := strings.Fields(lines[0]), []string{"Year", "Tax", "Year", "Ages", "Savings", "Pension", "Need", "Withdrawn", "Tax", "Raised", "Balance", "Shortfall"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("header: got %q, want %q", got, want)
+	}
+	year1 := strings.Fields(lines[1])
+	if got, want := strings.Join(year1, " "), "1 2025/26 67/63 "+strings.Join([]string{formatAmount(30000), formatAmount(480000), formatAmount(30000), formatAmount(30000), formatAmount(1486), formatAmount(510000), formatAmount(0)}, " "); got != want {
+		t.Errorf("year 1: got %q, want %q", got, want)
+	}
+	if year2 := strings.Fields(lines[2]); year2[len(year2)-1] != formatAmount(2486) {
+		t.Errorf("year 2: got shortfall %q, want %s", year2[len(year2)-1], formatAmount(2486))
+	}
+	if strings.Contains(b.String(), ansiRed) {
+		t.Error("coloured without colour set")
+	}
+
+	b.Reset()
+	if err := WriteTable(&b, testResult(), true); err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(b.String(), "\n")
+	if strings.HasPrefix(lines[1], ansiRed) || !strings.HasPrefix(lines[2], ansiRed) {
+		t.Errorf("want only the year with a shortfall in red:\n%q\n%q", lines[1], lines[2])
+	}
+}
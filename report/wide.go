@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// WriteWideCSV writes the result to w as a CSV table with a row for each year
+// and a balance and withdrawal column for each source, followed by the year's totals.
+func WriteWideCSV(w io.Writer, r drawdown.DrawResult) error {
+	years := historyYears(r.History)
+	balances := bySource(r.History, years, func(t drawdown.Transaction) (int64, bool) {
+		return t.Balance, true
+	})
+	withdrawals := bySource(r.History, years, func(t drawdown.Transaction) (int64, bool) {
+		return t.Amount, true
+	})
+	totals := totalsByYear(r, years)
+
+	cw := csv.NewWriter(w)
+	header := []string{"Year", "Tax Year", "Ages"}
+	for k := range balances {
+		header = append(header, balances[k].Name+" Balance", withdrawals[k].Name+" Withdrawal")
+	}
+	header = append(header, "Need", "Withdrawn", "Tax Raised", "Tax Paid", "Total Balance")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i, year := range years {
+		yt := totals[i]
+		row := []string{strconv.Itoa(year), yt.TaxYear, FormatAges(yt.Ages)}
+		for k := range balances {
+			row = append(row, itoa(balances[k].Values[i]), itoa(withdrawals[k].Values[i]))
+		}
+		row = append(row, itoa(yt.Need), itoa(yt.Withdrawn), itoa(yt.TaxRaised), itoa(yt.TaxPaid), itoa(yt.Balance))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// yearlyNeed returns the need recorded in the ledger for each of the years.
+func yearlyNeed(l drawdown.Ledger, years []int) []int64 {
+	need := make([]int64, len(years))
+	for _, e := range l {
+		if i := slices.Index(years, e.Year); i >= 0 && e.Kind == drawdown.NeedEntry {
+			need[i] += e.Amount
+		}
+	}
+	return need
+}
+
+func itoa(a int64) string {
+	return strconv.FormatInt(a, 10)
+}
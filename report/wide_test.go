@@ -0,0 +1,35 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestWriteWideCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteWideCSV(&b, testResult()); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d rows, want a header and a row for each of 2 years", len(records))
+	}
+	wantHeader := []string{"Year", "Tax Year", "Ages",
+		"Savings Balance", "Savings Withdrawal", "Pension Balance", "Pension Withdrawal", "Home Balance", "Home Withdrawal",
+		"Need", "Withdrawn", "Tax Raised", "Tax Paid", "Total Balance"}
+	if !reflect.DeepEqual(records[0], wantHeader) {
+		t.Errorf("header: got %q, want %q", records[0], wantHeader)
+	}
+	wantRows := [][]string{
+		{"1", "2025/26", "67/63", "30000", "10000", "480000", "20000", "400000", "0", "30000", "30000", "1486", "0", "510000"},
+		{"2", "2026/27", "68/64", "0", "30000", "0", "1000", "410000", "0", "32000", "31000", "0", "1486", "0"},
+	}
+	if !reflect.DeepEqual(records[1:], wantRows) {
+		t.Errorf("rows: got %q, want %q", records[1:], wantRows)
+	}
+}
@@ -0,0 +1,257 @@
+package report
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strings"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// Cell styles, indexes into the cellXfs of xlsxStyles.
+const (
+	styleDefault  = 0
+	styleCurrency = 1
+	styleHeader   = 2
+	styleRate     = 3
+)
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="2"><numFmt numFmtId="164" formatCode="&quot;£&quot;#,##0"/><numFmt numFmtId="165" formatCode="0.00"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="4">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+</styleSheet>
+`
+
+// xlsxCell is a value in a worksheet: a string, an int, an int64 or a float64.
+type xlsxCell struct {
+	value any
+	style int
+}
+
+type xlsxSheet struct {
+	name string
+	rows [][]xlsxCell
+}
+
+func text(s string) xlsxCell    { return xlsxCell{s, styleDefault} }
+func header(s string) xlsxCell  { return xlsxCell{s, styleHeader} }
+func number(n int) xlsxCell     { return xlsxCell{n, styleDefault} }
+func currency(a int64) xlsxCell { return xlsxCell{a, styleCurrency} }
+func rate(r float64) xlsxCell   { return xlsxCell{r, styleRate} }
+func headers(names ...string) []xlsxCell {
+	cells := make([]xlsxCell, len(names))
+	for i, name := range names {
+		cells[i] = header(name)
+	}
+	return cells
+}
+
+// WriteXLSX writes an Excel workbook of the result to w with sheets for the transactions,
+// the balance of each source by year, the summary and the inputs.
+func WriteXLSX(w io.Writer, r drawdown.DrawResult, in Inputs) error {
+	sheets := []xlsxSheet{
+		transactionsSheet(r.History),
+		balancesSheet(r.History),
+		summarySheet(r.Summary()),
+		inputsSheet(in),
+	}
+
+	z := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes(len(sheets))},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", workbook(sheets)},
+		{"xl/_rels/workbook.xml.rels", workbookRels(len(sheets))},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sh := range sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheet(sh)})
+	}
+	for _, f := range files {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+func transactionsSheet(h drawdown.DrawHistory) xlsxSheet {
+	sh := xlsxSheet{name: "Transactions"}
+	sh.rows = append(sh.rows, headers("Year", "Tax Year", "Ages", "Source", "Kind", "Opening Balance", "Growth", "Fees", "Deposits", "Withdrawals", "Amount", "Tax", "Tax Raised", "Balance"))
+	for _, t := range h {
+		sh.rows = append(sh.rows, []xlsxCell{
+			number(t.Year), text(t.TaxYear), text(FormatAges(t.Ages)), text(t.Source), text(t.Kind.String()),
+			currency(t.OpeningBalance), currency(t.Growth), currency(t.Fees), currency(t.Deposits), currency(t.Withdrawals),
+			currency(t.Amount), currency(t.Tax), currency(t.TaxRaised), currency(t.Balance),
+		})
+	}
+	return sh
+}
+
+// balancesSheet pivots the history into a row for each year, labelled with its tax year and ages,
+// with a column for the balance of each source.
+func balancesSheet(h drawdown.DrawHistory) xlsxSheet {
+	sh := xlsxSheet{name: "Balances"}
+	years := historyYears(h)
+	ss := bySource(h, years, func(t drawdown.Transaction) (int64, bool) {
+		return t.Balance, true
+	})
+	labels := yearLabels(h, years)
+	names := []string{"Year", "Tax Year", "Ages"}
+	for _, sr := range ss {
+		names = append(names, sr.Name)
+	}
+	sh.rows = append(sh.rows, headers(append(names, "Total Liquid")...))
+	liquid := map[int]int64{}
+	for _, t := range h {
+		if t.Kind == drawdown.Liquid {
+			liquid[t.Year] += t.Balance
+		}
+	}
+	for i, year := range years {
+		row := []xlsxCell{number(year), text(labels[i].TaxYear), text(FormatAges(labels[i].Ages))}
+		for _, sr := range ss {
+			row = append(row, currency(sr.Values[i]))
+		}
+		sh.rows = append(sh.rows, append(row, currency(liquid[year])))
+	}
+	return sh
+}
+
+func summarySheet(s drawdown.DrawSummary) xlsxSheet {
+	return xlsxSheet{name: "Summary", rows: [][]xlsxCell{
+		headers("Measure", "Value"),
+		{text("Total Withdrawn"), currency(s.TotalWithdrawn)},
+		{text("Total Tax Raised"), currency(s.TotalTaxRaised)},
+		{text("Final Balance"), currency(s.FinalBalance)},
+		{text("Real Final Balance"), currency(s.RealFinalBalance)},
+		{text("Final Estate"), currency(s.FinalEstate)},
+		{text("Final Year"), number(s.FinalYear)},
+		{text("Shortfall Years"), number(s.ShortfallYears)},
+		{text("First Shortfall Year"), number(s.FirstShortfallYear)},
+		{text("Total Shortfall"), currency(s.TotalShortfall)},
+	}}
+}
+
+func inputsSheet(in Inputs) xlsxSheet {
+	return xlsxSheet{name: "Inputs", rows: [][]xlsxCell{
+		headers("Input", "Value"),
+		{text("Scenario"), text(in.Scenario)},
+		{text("Years"), number(in.Years)},
+		{text("Year 1 Annual Income"), currency(int64(in.Year1AnnualIncome))},
+		{text("Investment Growth Rate %"), rate(in.Rates.InvestmentGrowthRate)},
+		{text("Savings Growth Rate %"), rate(in.Rates.SavingsGrowthRate)},
+		{text("Annual Inflation Rate %"), rate(in.Rates.AnnualInflationRate)},
+		{text("Platform Charge Rate %"), rate(in.Rates.PlatformChargeRate)},
+		{text("Tax Band Annual Percentage Increase %"), rate(in.Rates.TaxBandAnnualPctIncrease)},
+		{text("House Price Growth Rate %"), rate(in.Rates.HousePriceGrowthRate)},
+		{text("Care Cost Inflation Rate %"), rate(in.Rates.CareCostInflationRate)},
+		{text("Monthly"), yesNo(in.Monthly)},
+		{text("Continue After Shortfall"), yesNo(in.ContinueAfterShortfall)},
+		{text("Seed"), xlsxCell{in.Seed, styleDefault}},
+		{text("In Today's Money"), yesNo(in.Real)},
+	}}
+}
+
+func yesNo(b bool) xlsxCell {
+	if b {
+		return text("Yes")
+	}
+	return text("No")
+}
+
+func contentTypes(sheets int) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func workbook(sheets []xlsxSheet) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sh := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sh.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRels(sheets int) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func worksheet(sh xlsxSheet) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range sh.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := columnName(c) + fmt.Sprint(r+1)
+			switch v := cell.value.(type) {
+			case string:
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, cell.style, xmlEscape(v))
+			default:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%v</v></c>`, ref, cell.style, v)
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// columnName returns the spreadsheet name of the zero-based column c, such as "A" or "AB".
+func columnName(c int) string {
+	name := ""
+	for c++; c > 0; c = (c - 1) / 26 {
+		name = string(rune('A'+(c-1)%26)) + name
+	}
+	return name
+}
+
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+
+func xmlEscape(s string) string {
+	return xmlEscaper.Replace(s)
+}
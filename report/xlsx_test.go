@@ -0,0 +1,81 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	var b bytes.Buffer
+	in := Inputs{Scenario: `Ivy & "Co"`, Years: 2, Year1AnnualIncome: 30000, Monthly: true, Seed: 7, Real: true}
+	if err := WriteXLSX(&b, testResult(), in); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(content)
+		checkWellFormed(t, f.Name, string(content))
+	}
+	names := []string{}
+	for name := range parts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	want := []string{
+		"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/workbook.xml",
+		"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml", "xl/worksheets/sheet3.xml", "xl/worksheets/sheet4.xml",
+	}
+	if !slices.Equal(names, want) {
+		t.Fatalf("got parts %q, want %q", names, want)
+	}
+	for _, sheet := range []string{"Transactions", "Balances", "Summary", "Inputs"} {
+		if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="`+sheet+`"`) {
+			t.Errorf("the workbook has no %s sheet", sheet)
+		}
+	}
+	// A header and a row for each transaction.
+	if rows := strings.Count(parts["xl/worksheets/sheet1.xml"], "<row "); rows != 7 {
+		t.Errorf("the transactions sheet has %d rows, want 7", rows)
+	}
+	for sheet, want := range map[string]string{
+		"xl/worksheets/sheet1.xml": `<c r="B2" s="0" t="inlineStr"><is><t>2025/26</t></is></c><c r="C2" s="0" t="inlineStr"><is><t>67/63</t>`,
+		"xl/worksheets/sheet2.xml": `<c r="B3" s="0" t="inlineStr"><is><t>2026/27</t></is></c><c r="C3" s="0" t="inlineStr"><is><t>68/64</t>`,
+	} {
+		if !strings.Contains(parts[sheet], want) {
+			t.Errorf("%s is not labelled with the tax year and ages: it has no %s", sheet, want)
+		}
+	}
+	if want := `<t>Total Tax Raised</t></is></c><c r="B3" s="1"><v>1486</v></c>`; !strings.Contains(parts["xl/worksheets/sheet3.xml"], want) {
+		t.Errorf("the summary sheet does not contain %s", want)
+	}
+	for _, want := range []string{`<t>Monthly</t></is></c><c r="B12" s="0" t="inlineStr"><is><t>Yes</t>`, `<t>Continue After Shortfall</t></is></c><c r="B13" s="0" t="inlineStr"><is><t>No</t>`,
+		`<t>Seed</t></is></c><c r="B14" s="0"><v>7</v>`, `<t>In Today&apos;s Money</t></is></c><c r="B15" s="0" t="inlineStr"><is><t>Yes</t>`} {
+		if !strings.Contains(parts["xl/worksheets/sheet4.xml"], want) {
+			t.Errorf("the inputs sheet does not contain %s", want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for c, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(c); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", c, got, want)
+		}
+	}
+}
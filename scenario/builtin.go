@@ -0,0 +1,37 @@
+package scenario
+
+import (
+	"slices"
+	"strings"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// builtIn are the scenarios which can be chosen by name.
+var builtIn = map[string]func() *drawdown.DrawScenario{
+	"Ivy":         NewIvyDrawScenario,
+	"IvyCare":     NewIvyCareDrawScenario,
+	"IvyCareRisk": NewIvyCareRiskDrawScenario,
+	"Simple":      NewSimpleDrawScenario,
+}
+
+// Names returns the names of the built-in scenarios in alphabetical order.
+func Names() []string {
+	names := []string{}
+	for name := range builtIn {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New returns a new instance of the named built-in scenario, ignoring case,
+// or nil if there is no such scenario.
+func New(name string) *drawdown.DrawScenario {
+	for n, f := range builtIn {
+		if strings.EqualFold(n, name) {
+			return f()
+		}
+	}
+	return nil
+}
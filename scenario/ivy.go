@@ -1,18 +1,29 @@
 package scenario
 
 import (
+	"time"
+
 	drawdown "github.com/vextasy/drawdown/app"
 )
 
 func NewIvyDrawScenario() *drawdown.DrawScenario {
+	return drawdown.Define(ivy)
+}
+
+func ivy() *drawdown.DrawScenario {
 	s := &drawdown.DrawScenario{
 		Rates: drawdown.DrawRates{},
 	}
 
+	// Dates
+	calendar := drawdown.NewTaxYearCalendar(2025)
+	person1 := drawdown.Person{Name: "Person 1", DateOfBirth: time.Date(1957, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	person2 := drawdown.Person{Name: "Person 2", DateOfBirth: time.Date(1961, time.September, 1, 0, 0, 0, 0, time.UTC)}
+
 	// State Pension
 	const (
 		StatePensionYear0Amount       = 10000
-		StatePensionStartingYear      = 4
+		StatePensionAge               = 67
 		StatePensionAnnualPctIncrease = 2.5
 
 		// Savings
@@ -26,14 +37,14 @@ func NewIvyDrawScenario() *drawdown.DrawScenario {
 	)
 
 	// Sources
-	is_state_pension_1 := drawdown.NewStatePension("State Pension 1", StatePensionYear0Amount, StatePensionAnnualPctIncrease, 0)
-	is_state_pension_2 := drawdown.NewStatePension("State Pension 2", StatePensionYear0Amount, StatePensionAnnualPctIncrease, StatePensionStartingYear)
+	is_state_pension_1 := drawdown.NewStatePension("State Pension 1", StatePensionYear0Amount, StatePensionAnnualPctIncrease, calendar.YearAtAge(person1, StatePensionAge))
+	is_state_pension_2 := drawdown.NewStatePension("State Pension 2", StatePensionYear0Amount, StatePensionAnnualPctIncrease, calendar.YearAtAge(person2, StatePensionAge))
 
 	is_savings := drawdown.NewSavingsAccount("Savings", SavingsInitialBalance, &s.Rates.SavingsGrowthRate)
 
-	is_pension_1 := drawdown.NewInvestmentAccount("Pension 1", Pension1InitialBalance, &s.Rates.InvestmentGrowthRate)
+	is_pension_1 := drawdown.NewPensionAccount("Pension 1", Pension1InitialBalance, &s.Rates.InvestmentGrowthRate)
 
-	is_gia := drawdown.NewInvestmentAccount("GIA", GiaInitialBalance, &s.Rates.InvestmentGrowthRate)
+	is_gia := drawdown.NewInvestmentAccount("GIA", GiaInitialBalance, &s.Rates.InvestmentGrowthRate).Taxable()
 
 	// Tax Regimes
 	incomeTaxRegime := drawdown.NewTaxRegime([]drawdown.RateBound{
@@ -99,7 +110,7 @@ func NewIvyDrawScenario() *drawdown.DrawScenario {
 		},
 	}
 
-	return s.WithComponents(
+	return s.WithCalendar(calendar, person1, person2).WithComponents(
 		allSources,
 		drawSequence,
 		taxPaymentSequence,
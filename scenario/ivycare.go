@@ -0,0 +1,69 @@
+package scenario
+
+import (
+	"math"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// NewIvyCareDrawScenario is the Ivy scenario with residential care needed from year 20 until the end of the run.
+// The local authority means test applies but the home is disregarded.
+// The fees rise at the care cost inflation rate, which is independent of the annual inflation rate
+// and is 0 unless it is set (the command sets it to the default annual inflation rate).
+func NewIvyCareDrawScenario() *drawdown.DrawScenario {
+	return drawdown.Define(ivyCare)
+}
+
+// NewIvyCareRiskDrawScenario is the Ivy scenario with a risk of needing residential care for three years,
+// starting at random with a probability which doubles every five years of age.
+// The year in which care starts is drawn using the scenario's Seed.
+// The local authority means test applies but the home is disregarded.
+func NewIvyCareRiskDrawScenario() *drawdown.DrawScenario {
+	return drawdown.Define(ivyCareRisk)
+}
+
+func ivyCare() *drawdown.DrawScenario {
+	s := ivy()
+
+	const (
+		CareYear1AnnualFees = 60000
+		CareStartYear       = 20
+	)
+
+	return s.WithCare(&drawdown.Care{
+		Year1AnnualFees: CareYear1AnnualFees,
+		StartYear:       CareStartYear,
+		MeansTest:       inflationLinkedMeansTest(s),
+	})
+}
+
+func ivyCareRisk() *drawdown.DrawScenario {
+	s := ivy()
+
+	const (
+		CareYear1AnnualFees = 60000
+		CareYears           = 3
+		CareProbabilityAt70 = 0.005 // The probability of care starting in the year of age 70.
+	)
+
+	return s.WithCare(&drawdown.Care{
+		Year1AnnualFees: CareYear1AnnualFees,
+		Years:           CareYears,
+		Year1Age:        s.Calendar.Age(s.People[0], 1), // Care is for Person 1.
+		ProbabilityByAge: func(age int) float64 {
+			return min(1, CareProbabilityAt70*math.Pow(2, float64(age-70)/5))
+		},
+		MeansTest: inflationLinkedMeansTest(s),
+	})
+}
+
+// inflationLinkedMeansTest returns the English means test, disregarding the home, with its limits and allowance rising with inflation in s.
+func inflationLinkedMeansTest(s *drawdown.DrawScenario) *drawdown.MeansTest {
+	meansTest := drawdown.NewEnglishMeansTest(false)
+	s.InflationLinkedVariables = append(s.InflationLinkedVariables,
+		&meansTest.UpperCapitalLimit,
+		&meansTest.LowerCapitalLimit,
+		&meansTest.PersonalExpensesAllowance,
+	)
+	return meansTest
+}
@@ -0,0 +1,10 @@
+package scenario
+
+import "testing"
+
+func TestIvyCareRiskAgeComesFromTheCalendar(t *testing.T) {
+	// Person 1, born on 1 March 1957, is 68 at the start of the 2025/26 tax year.
+	if got := NewIvyCareRiskDrawScenario().Care.Year1Age; got != 68 {
+		t.Errorf("year 1 age %d, want 68", got)
+	}
+}
@@ -5,44 +5,24 @@ import (
 )
 
 func NewSimpleDrawScenario() *drawdown.DrawScenario {
-	s := &drawdown.DrawScenario{
-		Rates: drawdown.DrawRates{},
-	}
+	return drawdown.Define(simple)
+}
 
-	// State Pension
+func simple() *drawdown.DrawScenario {
 	const (
 		StatePensionYear0Amount       = 10000
 		StatePensionStartingYear      = 1
 		StatePensionAnnualPctIncrease = 2.5
-
-		// Savings
-		SavingsInitialBalance = 40000
-		IsaInitialBalance     = 40000
-
-		// Pension
-		Pension1InitialBalance = 350000
-		Pension2InitialBalance = 150000
-
-		// Investments
-		GiaInitialBalance = 50000
+		SavingsInitialBalance         = 40000
+		IsaInitialBalance             = 40000
+		Pension1InitialBalance        = 350000 // A quarter is taken as a tax free lump sum (TFLS) into savings.
+		Pension2InitialBalance        = 150000
+		GiaInitialBalance             = 50000
 	)
+	b := drawdown.NewBuilder()
+	r := b.Rates()
 
-	// Sources
-	is_state_pension_1 := drawdown.NewStatePension("State Pension 1", StatePensionYear0Amount, StatePensionAnnualPctIncrease, 0)
-	is_state_pension_2 := drawdown.NewStatePension("State Pension 2", StatePensionYear0Amount, StatePensionAnnualPctIncrease, StatePensionStartingYear)
-
-	is_savings := drawdown.NewSavingsAccount("Savings", SavingsInitialBalance, &s.Rates.SavingsGrowthRate)
-	is_isa := drawdown.NewInvestmentAccount("ISA", IsaInitialBalance, &s.Rates.InvestmentGrowthRate)
-
-	is_pension_1 := drawdown.NewInvestmentAccount("Pension 1", 0.75*Pension1InitialBalance, &s.Rates.InvestmentGrowthRate)
-	is_pension_1_tfls := drawdown.NewSavingsAccount("TFLS 1", 0.25*Pension1InitialBalance, &s.Rates.SavingsGrowthRate)
-
-	is_pension_2 := drawdown.NewInvestmentAccount("Pension 2", 0.75*Pension2InitialBalance, &s.Rates.InvestmentGrowthRate)
-	is_pension_2_tfls := drawdown.NewSavingsAccount("TFLS 2", 0.25*Pension2InitialBalance, &s.Rates.SavingsGrowthRate)
-
-	is_gia := drawdown.NewInvestmentAccount("GIA", GiaInitialBalance, &s.Rates.InvestmentGrowthRate)
-
-	// Tax Regimes
+	// Tax
 	incomeTaxRegime := drawdown.NewTaxRegime([]drawdown.RateBound{
 		drawdown.NewRateBound(12540, 0.0),
 		drawdown.NewRateBound(50270, 20.0),
@@ -53,94 +33,36 @@ func NewSimpleDrawScenario() *drawdown.DrawScenario {
 		drawdown.NewRateBound(3000, 0.0),
 		drawdown.NewRateBound(drawdown.HighUpperBound, 18.0),
 	})
-	taxRegimes := []*drawdown.TaxRegime{&incomeTaxRegime, &capitalGainsTaxRegime}
-
-	// Tax Accounts
-	incomeTaxAccount1 := drawdown.NewTaxAccount("Income Tax 1", incomeTaxRegime)
-	incomeTaxAccount2 := drawdown.NewTaxAccount("Income Tax 2", incomeTaxRegime)
-	capitalGainsTaxAccount := drawdown.NewTaxAccount("Capital Gains Tax 1", capitalGainsTaxRegime)
-	taxAccounts := map[*drawdown.Source]*drawdown.TaxAccount{
-		is_state_pension_1: incomeTaxAccount1,
-		is_state_pension_2: incomeTaxAccount2,
-		is_pension_1:       incomeTaxAccount1,
-		is_pension_2:       incomeTaxAccount2,
-		is_gia:             capitalGainsTaxAccount,
-	}
-
-	// Inflation linked variables
-	incomeTaxAllowance := incomeTaxRegime.TaxFreeAllowance()
-	capitalGainsTaxAllowance := capitalGainsTaxRegime.TaxFreeAllowance()
-	annualMaximumIsaContribution := int64(20000)
-	v1000 := int64(1000)
-
-	allInflationLinkedVariables := []*int64{
-		&annualMaximumIsaContribution,
-		&incomeTaxAllowance,
-		&capitalGainsTaxAllowance,
-		&v1000,
-	}
-
-	// The full set of sources
-	allSources := []*drawdown.Source{
-		is_state_pension_1,
-		is_state_pension_2,
-		is_pension_1,
-		is_pension_1_tfls,
-		is_pension_2,
-		is_pension_2_tfls,
-		is_savings,
-		is_isa,
-		is_gia,
-	}
-
-	// The order in which to draw from the sources
-	// drawdown.Seq draws upto an amount from one or more sources in order.
-	// drawdown.Split draws from two sources using a percentage split.
-	drawSequence := []*drawdown.Source{
-		is_state_pension_1,
-		is_state_pension_2,
-		drawdown.Seq(&capitalGainsTaxAllowance, is_gia),
-		drawdown.Seq(&v1000, is_pension_1_tfls, is_savings, is_pension_2_tfls),
-		is_isa,
-		is_pension_1_tfls,
-		is_pension_2_tfls,
-		is_pension_1,
-		is_pension_2,
-		is_savings,
-		is_gia,
-	}
-
-	// The order in which to consider sources for the payment of tax.
-	taxPaymentSequence := []*drawdown.Source{
-		is_isa,
-		is_pension_1_tfls,
-		is_pension_2_tfls,
-		is_savings,
-		is_gia,
-		is_pension_1,
-		is_pension_2,
-	}
-
-	// Actions are performed at the start of the year
-	// after the sources and tax accounts have been initialised
-	// and before any withdrawals are made.
-	actions := []func(year int, need int64, s *drawdown.DrawScenario){
-		func(year int, need int64, s *drawdown.DrawScenario) {
-			//fmt.Println("year", year, "need", need)
-		},
-		func(year int, need int64, s *drawdown.DrawScenario) {
-			// Every year make the maximum annual ISA contribution from the savings accounts.
-			drawdown.Transfer(&annualMaximumIsaContribution, is_isa, is_savings, is_pension_1_tfls, is_pension_2_tfls)
-		},
-	}
-
-	return s.WithComponents(
-		allSources,
-		drawSequence,
-		taxPaymentSequence,
-		taxAccounts,
-		taxRegimes,
-		actions,
-		allInflationLinkedVariables,
-	)
+	incomeTax1 := b.TaxWith("Income Tax 1", incomeTaxRegime)
+	incomeTax2 := b.TaxWith("Income Tax 2", incomeTaxRegime)
+	capitalGainsTax := b.TaxWith("Capital Gains Tax 1", capitalGainsTaxRegime)
+
+	// Sources, in the order in which they are reported
+	statePension1 := b.AddSource(drawdown.NewStatePension("State Pension 1", StatePensionYear0Amount, StatePensionAnnualPctIncrease, 0), incomeTax1)
+	statePension2 := b.AddSource(drawdown.NewStatePension("State Pension 2", StatePensionYear0Amount, StatePensionAnnualPctIncrease, StatePensionStartingYear), incomeTax2)
+	pension1 := b.AddSource(drawdown.NewPensionAccount("Pension 1", 0.75*Pension1InitialBalance, &r.InvestmentGrowthRate), incomeTax1)
+	tfls1 := b.AddSource(drawdown.NewSavingsAccount("TFLS 1", 0.25*Pension1InitialBalance, &r.SavingsGrowthRate))
+	pension2 := b.AddSource(drawdown.NewPensionAccount("Pension 2", 0.75*Pension2InitialBalance, &r.InvestmentGrowthRate), incomeTax2)
+	tfls2 := b.AddSource(drawdown.NewSavingsAccount("TFLS 2", 0.25*Pension2InitialBalance, &r.SavingsGrowthRate))
+	savings := b.AddSource(drawdown.NewSavingsAccount("Savings", SavingsInitialBalance, &r.SavingsGrowthRate))
+	isa := b.AddSource(drawdown.NewInvestmentAccount("ISA", IsaInitialBalance, &r.InvestmentGrowthRate))
+	gia := b.AddSource(drawdown.NewInvestmentAccount("GIA", GiaInitialBalance, &r.InvestmentGrowthRate).Taxable(), capitalGainsTax)
+
+	// Amounts which rise with inflation
+	isaAllowance := b.LinkToInflation(20000)
+	capitalGainsTaxAllowance := b.LinkToInflation(capitalGainsTaxRegime.TaxFreeAllowance())
+	v1000 := b.LinkToInflation(1000)
+
+	return b.
+		// Use the capital gains allowance and a little cash first, then the ISA, the TFLS and the pensions.
+		DrawFrom(statePension1, statePension2,
+			drawdown.Seq(capitalGainsTaxAllowance, gia),
+			drawdown.Seq(v1000, tfls1, savings, tfls2),
+			isa, tfls1, tfls2, pension1, pension2, savings, gia).
+		PayTaxFrom(isa, tfls1, tfls2, savings, gia, pension1, pension2).
+		// Every year make the maximum annual ISA contribution from the savings accounts.
+		EveryYear(func(year int, need int64, s *drawdown.DrawScenario) {
+			drawdown.Transfer(isaAllowance, isa, savings, tfls1, tfls2)
+		}).
+		MustBuild()
 }
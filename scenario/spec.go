@@ -0,0 +1,249 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+// Spec describes a scenario as data, so that one can be read from JSON rather than written in Go.
+// Tax regimes, tax accounts and sources refer to each other by name.
+//
+//	{
+//		"Name": "Mine",
+//		"FirstTaxYear": 2025,
+//		"People": [{"Name": "Me", "DateOfBirth": "1960-05-01"}],
+//		"TaxRegimes": [{"Name": "Income Tax", "Bands": [{"UpTo": 12570, "Rate": 0}, {"UpTo": 50270, "Rate": 20}, {"Rate": 40}]}],
+//		"TaxAccounts": [{"Name": "Income Tax", "Regime": "Income Tax"}],
+//		"Sources": [
+//			{"Name": "State Pension", "Kind": "StatePension", "Balance": 11500, "AnnualPctIncrease": 2.5, "StartYear": 2, "TaxAccount": "Income Tax"},
+//			{"Name": "Savings", "Kind": "Savings", "Balance": 40000},
+//			{"Name": "Pension", "Kind": "Pension", "Balance": 400000, "TaxAccount": "Income Tax"}
+//		],
+//		"DrawSequence": [{"Sources": ["State Pension"]}, {"Sources": ["Savings"], "Limit": 5000}, {"Sources": ["Pension"]}],
+//		"PayTaxFrom": ["Savings", "Pension"]
+//	}
+type Spec struct {
+	Name         string
+	FirstTaxYear int          // The year in which the first tax year starts, such as 2025 for 2025/26, or 0 if the years are not tied to dates.
+	People       []PersonSpec // The people whose ages label the results when there is a FirstTaxYear.
+	TaxRegimes   []RegimeSpec
+	TaxAccounts  []AccountSpec
+	Sources      []SourceSpec // In the order in which they are reported.
+	DrawSequence []EntrySpec
+	PayTaxFrom   []string // The names of the sources from which tax is paid, in order.
+}
+
+// PersonSpec describes a person whose age labels the results.
+type PersonSpec struct {
+	Name        string
+	DateOfBirth string // Such as "1960-05-01".
+}
+
+// RegimeSpec describes a tax regime by its bands, in increasing order.
+type RegimeSpec struct {
+	Name  string
+	Bands []BandSpec
+}
+
+// BandSpec is a band of a tax regime. A band with no UpTo covers every amount above the band before it.
+type BandSpec struct {
+	UpTo int64
+	Rate float64 // Such as 20 for 20%.
+}
+
+// AccountSpec describes a tax account under one of the tax regimes.
+// Sources taxed in the same account share its bands.
+type AccountSpec struct {
+	Name   string
+	Regime string
+}
+
+// Kinds of SourceSpec.
+const (
+	SavingsKind      = "Savings"
+	InvestmentKind   = "Investment"
+	PensionKind      = "Pension"
+	StatePensionKind = "StatePension"
+	LoanKind         = "Loan"
+)
+
+// SourceSpec describes a source. Savings and investments grow at the scenario's savings and investment growth rates.
+type SourceSpec struct {
+	Name              string
+	Kind              string  // Savings, Investment, Pension, StatePension or Loan.
+	Balance           int64   // The opening balance, the annual amount of a state pension in year 1, or the amount outstanding on a loan.
+	Taxable           bool    // Withdrawals from an investment, such as a GIA, are taxed in its tax account.
+	TaxAccount        string  // The tax account of a pension, a state pension or a taxable investment.
+	AnnualPctIncrease float64 // The annual increase of a state pension.
+	StartYear         int     // The year in which a state pension starts.
+	RatePct           float64 // The interest rate of a loan.
+	TermYears         int     // The term of a loan.
+}
+
+// EntrySpec is an entry in the draw sequence: a single source, several sources drawn in turn,
+// or, when Pcts are given, two sources drawn in the given percentages.
+type EntrySpec struct {
+	Sources []string
+	Limit   int64   // The most drawn in year 1, which then rises with inflation, or 0 for no limit.
+	Pcts    []int64 // For a split of two sources, the percentage drawn from each.
+}
+
+// Parse reads a Spec from JSON, rejecting any field it does not know.
+func Parse(data []byte) (*Spec, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var sp Spec
+	if err := dec.Decode(&sp); err != nil {
+		return nil, err
+	}
+	return &sp, nil
+}
+
+// Build returns a new instance of the scenario described by the spec, which can be cloned.
+// It returns an error if the spec refers to something it does not describe,
+// or the Problems found by validating the scenario.
+func (sp *Spec) Build() (*drawdown.DrawScenario, error) {
+	if err := sp.check(); err != nil {
+		return nil, err
+	}
+	return drawdown.DefineWith(sp.define)
+}
+
+// check reports the first mistake in the spec which would stop it being built.
+// Mistakes in the scenario built, such as a pension without a tax account, are left to Validate.
+func (sp *Spec) check() error {
+	if sp.Name == "" {
+		return errors.New("the scenario has no name")
+	}
+	regimes, accounts, sources := []string{}, []string{}, []string{}
+	for _, r := range sp.TaxRegimes {
+		if slices.Contains(regimes, r.Name) {
+			return fmt.Errorf("tax regime %q is described more than once", r.Name)
+		}
+		regimes = append(regimes, r.Name)
+	}
+	for _, a := range sp.TaxAccounts {
+		if slices.Contains(accounts, a.Name) {
+			return fmt.Errorf("tax account %q is described more than once", a.Name)
+		}
+		if !slices.Contains(regimes, a.Regime) {
+			return fmt.Errorf("tax account %q has unknown regime %q", a.Name, a.Regime)
+		}
+		accounts = append(accounts, a.Name)
+	}
+	for _, src := range sp.Sources {
+		if slices.Contains(sources, src.Name) {
+			return fmt.Errorf("source %q is described more than once", src.Name)
+		}
+		if !slices.Contains([]string{SavingsKind, InvestmentKind, PensionKind, StatePensionKind, LoanKind}, src.Kind) {
+			return fmt.Errorf("source %q has unknown kind %q", src.Name, src.Kind)
+		}
+		if src.TaxAccount != "" && !slices.Contains(accounts, src.TaxAccount) {
+			return fmt.Errorf("source %q has unknown tax account %q", src.Name, src.TaxAccount)
+		}
+		sources = append(sources, src.Name)
+	}
+	for i, e := range sp.DrawSequence {
+		if len(e.Sources) == 0 {
+			return fmt.Errorf("draw sequence entry %d has no sources", i+1)
+		}
+		if e.Pcts != nil && (len(e.Sources) != 2 || len(e.Pcts) != 2) {
+			return fmt.Errorf("draw sequence entry %d splits other than two sources in two percentages", i+1)
+		}
+		if e.Pcts != nil && e.Limit != 0 {
+			return fmt.Errorf("draw sequence entry %d has both a split and a limit", i+1)
+		}
+		for _, name := range e.Sources {
+			if !slices.Contains(sources, name) {
+				return fmt.Errorf("draw sequence entry %d has unknown source %q", i+1, name)
+			}
+		}
+	}
+	for _, name := range sp.PayTaxFrom {
+		if !slices.Contains(sources, name) {
+			return fmt.Errorf("tax is paid from unknown source %q", name)
+		}
+	}
+	for _, p := range sp.People {
+		if _, err := time.Parse(time.DateOnly, p.DateOfBirth); err != nil {
+			return fmt.Errorf("the date of birth of %q: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// define builds the scenario, which check has found to be consistent.
+func (sp *Spec) define(b *drawdown.Builder) {
+	r := b.Rates()
+	regimes := map[string]drawdown.TaxRegime{}
+	for _, rs := range sp.TaxRegimes {
+		bounds := []drawdown.RateBound{}
+		for _, band := range rs.Bands {
+			upper := band.UpTo
+			if upper == 0 {
+				upper = drawdown.HighUpperBound
+			}
+			bounds = append(bounds, drawdown.NewRateBound(upper, band.Rate))
+		}
+		regimes[rs.Name] = drawdown.NewTaxRegime(bounds)
+	}
+	accounts := map[string]*drawdown.TaxAccount{}
+	for _, a := range sp.TaxAccounts {
+		accounts[a.Name] = b.TaxWith(a.Name, regimes[a.Regime])
+	}
+	sources := map[string]*drawdown.Source{}
+	for _, src := range sp.Sources {
+		var is *drawdown.Source
+		switch src.Kind {
+		case SavingsKind:
+			is = drawdown.NewSavingsAccount(src.Name, src.Balance, &r.SavingsGrowthRate)
+		case InvestmentKind:
+			is = drawdown.NewInvestmentAccount(src.Name, src.Balance, &r.InvestmentGrowthRate)
+			if src.Taxable {
+				is.Taxable()
+			}
+		case PensionKind:
+			is = drawdown.NewPensionAccount(src.Name, src.Balance, &r.InvestmentGrowthRate)
+		case StatePensionKind:
+			is = drawdown.NewStatePension(src.Name, src.Balance, src.AnnualPctIncrease, src.StartYear)
+		case LoanKind:
+			is = drawdown.NewLoan(src.Name, src.Balance, src.RatePct, src.TermYears)
+		}
+		sources[src.Name] = b.AddSource(is, accounts[src.TaxAccount])
+	}
+	for _, e := range sp.DrawSequence {
+		named := []*drawdown.Source{}
+		for _, name := range e.Sources {
+			named = append(named, sources[name])
+		}
+		switch {
+		case e.Pcts != nil:
+			b.DrawFrom(drawdown.Split(named[0], named[1], e.Pcts[0], e.Pcts[1]))
+		case e.Limit != 0:
+			b.DrawFrom(drawdown.Seq(b.LinkToInflation(e.Limit), named...))
+		case len(named) == 1:
+			b.DrawFrom(named[0])
+		default:
+			noLimit := int64(math.MaxInt64)
+			b.DrawFrom(drawdown.Seq(&noLimit, named...))
+		}
+	}
+	for _, name := range sp.PayTaxFrom {
+		b.PayTaxFrom(sources[name])
+	}
+	if sp.FirstTaxYear != 0 {
+		people := []drawdown.Person{}
+		for _, p := range sp.People {
+			dob, _ := time.Parse(time.DateOnly, p.DateOfBirth)
+			people = append(people, drawdown.Person{Name: p.Name, DateOfBirth: dob})
+		}
+		b.Calendar(drawdown.NewTaxYearCalendar(sp.FirstTaxYear), people...)
+	}
+}
@@ -0,0 +1,90 @@
+package scenario
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	drawdown "github.com/vextasy/drawdown/app"
+)
+
+const testSpec = `{
+	"Name": "Mine",
+	"FirstTaxYear": 2025,
+	"People": [{"Name": "Me", "DateOfBirth": "1960-05-01"}],
+	"TaxRegimes": [{"Name": "Income Tax", "Bands": [{"UpTo": 12570, "Rate": 0}, {"UpTo": 50270, "Rate": 20}, {"Rate": 40}]}],
+	"TaxAccounts": [{"Name": "Income Tax", "Regime": "Income Tax"}],
+	"Sources": [
+		{"Name": "Savings", "Kind": "Savings", "Balance": 40000},
+		{"Name": "Pension", "Kind": "Pension", "Balance": 400000, "TaxAccount": "Income Tax"}
+	],
+	"DrawSequence": [{"Sources": ["Savings"], "Limit": 5000}, {"Sources": ["Pension"]}],
+	"PayTaxFrom": ["Savings", "Pension"]
+}`
+
+func TestSpecBuild(t *testing.T) {
+	sp, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := sp.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.WithRates(drawdown.DrawRates{}).Iterate(2, 25000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{"Savings": 5000, "Pension": 20000}
+	for _, tr := range result.History {
+		if tr.Year == 1 && tr.Amount != want[tr.Source] {
+			t.Errorf("year 1: drew %d from %s, want %d", tr.Amount, tr.Source, want[tr.Source])
+		}
+	}
+	if s.Calendar == nil || s.Calendar.YearStart(1) != drawdown.NewTaxYearCalendar(2025).Start {
+		t.Errorf("calendar %v, want the tax year 2025/26 first", s.Calendar)
+	}
+	if _, err := s.Clone(); err != nil {
+		t.Errorf("Clone: %v", err)
+	}
+}
+
+func TestSpecErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(sp *Spec)
+		want   string
+	}{
+		{"no name", func(sp *Spec) { sp.Name = "" }, "no name"},
+		{"unknown regime", func(sp *Spec) { sp.TaxAccounts[0].Regime = "CGT" }, `unknown regime "CGT"`},
+		{"unknown kind", func(sp *Spec) { sp.Sources[0].Kind = "Shoebox" }, `unknown kind "Shoebox"`},
+		{"unknown tax account", func(sp *Spec) { sp.Sources[1].TaxAccount = "CGT" }, `unknown tax account "CGT"`},
+		{"unknown drawn source", func(sp *Spec) { sp.DrawSequence[1].Sources = []string{"ISA"} }, `unknown source "ISA"`},
+		{"split of one", func(sp *Spec) { sp.DrawSequence[1].Pcts = []int64{50, 50} }, "splits other than two"},
+		{"unknown tax payer", func(sp *Spec) { sp.PayTaxFrom = []string{"ISA"} }, `unknown source "ISA"`},
+		{"bad date of birth", func(sp *Spec) { sp.People[0].DateOfBirth = "1 May 1960" }, "date of birth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, err := Parse([]byte(testSpec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.change(sp)
+			if _, err := sp.Build(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	// A pension without a tax account is built, and found wanting by Validate.
+	sp, _ := Parse([]byte(testSpec))
+	sp.Sources[1].TaxAccount = ""
+	var problems drawdown.Problems
+	if _, err := sp.Build(); !errors.As(err, &problems) {
+		t.Errorf("got %v, want Problems", err)
+	}
+	if _, err := Parse([]byte(`{"Name": "Mine", "Colour": "blue"}`)); err == nil {
+		t.Error("parsed an unknown field")
+	}
+}
@@ -0,0 +1,321 @@
+// Package server exposes the drawdown engine as an HTTP JSON API.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	drawdown "github.com/vextasy/drawdown/app"
+	"github.com/vextasy/drawdown/report"
+	"github.com/vextasy/drawdown/scenario"
+)
+
+const (
+	MaxRequestBytes   = 1 << 20 // The largest request body accepted.
+	MaxYears          = 100     // The most years a scenario may be run for.
+	MaxSweepRuns      = 20000   // The most combinations of rates a sweep may run.
+	MaxMonteCarloRuns = 20000   // The most runs a Monte Carlo job may make.
+)
+
+// RunRequest asks for a built-in scenario, or one given by its Definition, to be run with the given rates.
+type RunRequest struct {
+	Scenario               string
+	Definition             *scenario.Spec // A scenario described in full, which is run instead of the named one.
+	Years                  int
+	Year1AnnualIncome      int
+	Rates                  drawdown.DrawRates
+	Monthly                bool
+	ContinueAfterShortfall bool
+	Seed                   int64 // The seed of random events, such as the start of care.
+}
+
+// SweepRequest asks for a scenario to be run with every combination of the listed rates.
+// A rate with no values listed takes its value from Rates.
+type SweepRequest struct {
+	RunRequest
+	InvestmentGrowthRates     []float64
+	SavingsGrowthRates        []float64
+	AnnualInflationRates      []float64
+	PlatformChargeRates       []float64
+	TaxBandAnnualPctIncreases []float64
+	HousePriceGrowthRates     []float64
+	CareCostInflationRates    []float64
+}
+
+// MonteCarloRequest asks for a scenario to be run Runs times, each with rates drawn at random
+// from normal distributions whose means are Rates and whose standard deviations are StdDev.
+// Each run also has its own seed of random events, drawn from Seed. The same Seed gives the same runs.
+type MonteCarloRequest struct {
+	RunRequest
+	StdDev drawdown.DrawRates
+	Runs   int
+	Seed   int64
+}
+
+// New returns a handler for the API, which abandons any request taking longer than timeout.
+//
+//	GET  /scenarios   the names of the built-in scenarios
+//	POST /run         run a scenario (RunRequest) and return its inputs, summary, events and history
+//	POST /sweep       run a sweep (SweepRequest) and return the summary for each combination of rates
+//	POST /montecarlo  run a Monte Carlo job (MonteCarloRequest) and return the summary of the runs and of each run
+func New(timeout time.Duration) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /scenarios", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, scenario.Names())
+	})
+	mux.HandleFunc("POST /run", func(w http.ResponseWriter, r *http.Request) {
+		var req RunRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		result, err := run(ctx, req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		logWriteError(r, report.WriteJSON(w, req.Inputs(), result))
+	})
+	mux.HandleFunc("POST /sweep", func(w http.ResponseWriter, r *http.Request) {
+		var req SweepRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		rows, err := sweep(ctx, req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		logWriteError(r, report.WriteSweepJSON(w, req.Inputs(), rows))
+	})
+	mux.HandleFunc("POST /montecarlo", func(w http.ResponseWriter, r *http.Request) {
+		var req MonteCarloRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		mc, summary, rows, err := monteCarlo(ctx, req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		logWriteError(r, report.WriteMonteCarloJSON(w, req.Inputs(), mc, summary, rows))
+	})
+	return mux
+}
+
+// badRequest is an error in what the client asked for.
+type badRequest struct{ reason string }
+
+func (e badRequest) Error() string { return e.reason }
+
+// Inputs returns the inputs of the run, as reported with its results.
+func (req RunRequest) Inputs() report.Inputs {
+	name := req.Scenario
+	if req.Definition != nil {
+		name = req.Definition.Name
+	}
+	return report.Inputs{Scenario: name, Years: req.Years, Year1AnnualIncome: req.Year1AnnualIncome, Rates: req.Rates,
+		Monthly: req.Monthly, ContinueAfterShortfall: req.ContinueAfterShortfall, Seed: req.Seed}
+}
+
+// NewScenario returns a new instance of the scenario to be run: the one defined, if any, or else the named built-in one.
+// A definition which cannot be built is a bad request, unless it is built and found invalid,
+// in which case its Problems are returned.
+func (req RunRequest) NewScenario() (*drawdown.DrawScenario, error) {
+	if req.Definition != nil {
+		s, err := req.Definition.Build()
+		if err != nil && !errors.As(err, new(drawdown.Problems)) {
+			return nil, badRequest{err.Error()}
+		}
+		return s, err
+	}
+	s := scenario.New(req.Scenario)
+	if s == nil {
+		return nil, badRequest{fmt.Sprintf("unknown scenario %q", req.Scenario)}
+	}
+	return s, nil
+}
+
+func (req RunRequest) check() error {
+	if _, err := req.NewScenario(); err != nil {
+		return err
+	}
+	if req.Years < 1 || req.Years > MaxYears {
+		return badRequest{fmt.Sprintf("years must be between 1 and %d", MaxYears)}
+	}
+	if req.Year1AnnualIncome < 0 {
+		return badRequest{"income must not be negative"}
+	}
+	return nil
+}
+
+// iterate runs the scenario with the given rates, giving up at the start of a year once ctx is done.
+func (req RunRequest) iterate(ctx context.Context, rates drawdown.DrawRates) (drawdown.DrawResult, error) {
+	s, err := req.NewScenario()
+	if err != nil {
+		return drawdown.DrawResult{}, err
+	}
+	s.WithRates(rates).WithContinueAfterShortfall(req.ContinueAfterShortfall).WithSeed(req.Seed)
+	if req.Monthly {
+		return s.IterateMonthlyContext(ctx, req.Years, req.Year1AnnualIncome)
+	}
+	return s.IterateContext(ctx, req.Years, req.Year1AnnualIncome)
+}
+
+func run(ctx context.Context, req RunRequest) (drawdown.DrawResult, error) {
+	if err := req.check(); err != nil {
+		return drawdown.DrawResult{}, err
+	}
+	return req.iterate(ctx, req.Rates)
+}
+
+// values returns the values to sweep for a rate, or its base value if none are listed.
+func values(listed []float64, base float64) []float64 {
+	if len(listed) == 0 {
+		return []float64{base}
+	}
+	return listed
+}
+
+func sweep(ctx context.Context, req SweepRequest) ([]report.SweepRow, error) {
+	if err := req.check(); err != nil {
+		return nil, err
+	}
+	b := req.Rates
+	// The values of each rate, with the field of the rates which they set.
+	axes := []struct {
+		values []float64
+		field  func(r *drawdown.DrawRates) *float64
+	}{
+		{values(req.InvestmentGrowthRates, b.InvestmentGrowthRate), func(r *drawdown.DrawRates) *float64 { return &r.InvestmentGrowthRate }},
+		{values(req.SavingsGrowthRates, b.SavingsGrowthRate), func(r *drawdown.DrawRates) *float64 { return &r.SavingsGrowthRate }},
+		{values(req.AnnualInflationRates, b.AnnualInflationRate), func(r *drawdown.DrawRates) *float64 { return &r.AnnualInflationRate }},
+		{values(req.PlatformChargeRates, b.PlatformChargeRate), func(r *drawdown.DrawRates) *float64 { return &r.PlatformChargeRate }},
+		{values(req.TaxBandAnnualPctIncreases, b.TaxBandAnnualPctIncrease), func(r *drawdown.DrawRates) *float64 { return &r.TaxBandAnnualPctIncrease }},
+		{values(req.HousePriceGrowthRates, b.HousePriceGrowthRate), func(r *drawdown.DrawRates) *float64 { return &r.HousePriceGrowthRate }},
+		{values(req.CareCostInflationRates, b.CareCostInflationRate), func(r *drawdown.DrawRates) *float64 { return &r.CareCostInflationRate }},
+	}
+	n := 1
+	for _, axis := range axes {
+		n *= len(axis.values)
+		if n > MaxSweepRuns {
+			break // Stop before the product of many long lists overflows.
+		}
+	}
+	if n > MaxSweepRuns {
+		return nil, badRequest{fmt.Sprintf("a sweep of more than %d runs is not allowed", MaxSweepRuns)}
+	}
+	rows := []report.SweepRow{}
+	var each func(k int, rates drawdown.DrawRates) error
+	each = func(k int, rates drawdown.DrawRates) error {
+		if k == len(axes) {
+			result, err := req.iterate(ctx, rates)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, report.SweepRow{Rates: rates, Summary: result.Summary()})
+			return nil
+		}
+		for _, v := range axes[k].values {
+			*axes[k].field(&rates) = v
+			if err := each(k+1, rates); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := each(0, b); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func monteCarlo(ctx context.Context, req MonteCarloRequest) (drawdown.MonteCarlo, drawdown.MonteCarloSummary, []report.SweepRow, error) {
+	mc := drawdown.MonteCarlo{Mean: req.Rates, StdDev: req.StdDev, Runs: req.Runs, Seed: req.Seed}
+	if err := req.check(); err != nil {
+		return mc, drawdown.MonteCarloSummary{}, nil, err
+	}
+	if req.Runs < 1 || req.Runs > MaxMonteCarloRuns {
+		return mc, drawdown.MonteCarloSummary{}, nil, badRequest{fmt.Sprintf("runs must be between 1 and %d", MaxMonteCarloRuns)}
+	}
+	rows := []report.SweepRow{}
+	summaries := []drawdown.DrawSummary{}
+	seeds := mc.Seeds()
+	for i, rates := range mc.Rates() {
+		run := req.RunRequest
+		run.Seed = seeds[i]
+		result, err := run.iterate(ctx, rates)
+		if err != nil {
+			return mc, drawdown.MonteCarloSummary{}, nil, err
+		}
+		summary := result.Summary()
+		rows = append(rows, report.SweepRow{Rates: rates, Summary: summary})
+		summaries = append(summaries, summary)
+	}
+	return mc, drawdown.SummarizeMonteCarlo(summaries), rows, nil
+}
+
+type errorBody struct {
+	Error string
+}
+
+// decode reads the JSON request body into v, replying with an error and returning false if it cannot.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeJSON(w, status, errorBody{err.Error()})
+		return false
+	}
+	return true
+}
+
+// writeError replies with the status appropriate to err.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var invalid *drawdown.InvalidScenario
+	switch {
+	case errors.As(err, new(badRequest)):
+		status = http.StatusBadRequest
+	case errors.As(err, &invalid):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return
+	}
+	writeJSON(w, status, errorBody{err.Error()})
+}
+
+// logWriteError logs an error in writing the response to r, which is too late to report to the client.
+func logWriteError(r *http.Request, err error) {
+	if err != nil {
+		log.Printf("%s %s: writing the response: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("writing the response: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// post sends body to the path of a handler with the given timeout and returns the response.
+func post(t *testing.T, timeout time.Duration, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	New(timeout).ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return w
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"run", "/run", `{"Scenario": "Simple", "Years": 2, "Year1AnnualIncome": 30000}`, http.StatusOK},
+		{"unknown field", "/run", `{"Scenario": "Simple", "Years": 2, "Colour": "blue"}`, http.StatusBadRequest},
+		{"unknown scenario", "/run", `{"Scenario": "Nobody", "Years": 2}`, http.StatusBadRequest},
+		{"too many years", "/run", fmt.Sprintf(`{"Scenario": "Simple", "Years": %d}`, MaxYears+1), http.StatusBadRequest},
+		{"too large", "/run", `{"Scenario": "` + strings.Repeat("a", MaxRequestBytes) + `"}`, http.StatusRequestEntityTooLarge},
+		{"invalid definition", "/run", `{"Years": 2, "Definition": {"Name": "Mine", "Sources": [{"Name": "Pension", "Kind": "Pension"}], "DrawSequence": [{"Sources": ["Pension"]}]}}`,
+			http.StatusUnprocessableEntity},
+		{"inconsistent definition", "/run", `{"Years": 2, "Definition": {"Name": "Mine", "DrawSequence": [{"Sources": ["Pension"]}]}}`, http.StatusBadRequest},
+		{"no runs", "/montecarlo", `{"Scenario": "Simple", "Years": 2, "Runs": 0}`, http.StatusBadRequest},
+		{"too many runs", "/montecarlo", fmt.Sprintf(`{"Scenario": "Simple", "Years": 2, "Runs": %d}`, MaxMonteCarloRuns+1), http.StatusBadRequest},
+		{"runs", "/montecarlo", `{"Scenario": "Simple", "Years": 1, "Runs": 2}`, http.StatusOK},
+		{"too large a sweep", "/sweep", fmt.Sprintf(`{"Scenario": "Simple", "Years": 2, "InvestmentGrowthRates": [%s], "SavingsGrowthRates": [%[1]s]}`,
+			strings.Repeat("1,", 200)+"1"), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(t, time.Minute, tt.path, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("status %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("content type %q, want JSON", ct)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	w := post(t, time.Nanosecond, "/run", fmt.Sprintf(`{"Scenario": "Ivy", "Years": %d, "Year1AnnualIncome": 30000}`, MaxYears))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status %d, want %d: %s", w.Code, http.StatusGatewayTimeout, w.Body)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	w := post(t, time.Minute, "/sweep", `{"Scenario": "IvyCare", "Years": 25, "Year1AnnualIncome": 30000,
+		"InvestmentGrowthRates": [3, 5], "HousePriceGrowthRates": [0, 2], "CareCostInflationRates": [1, 4, 8]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var got struct {
+		Rows []struct {
+			Rates struct{ InvestmentGrowthRate, HousePriceGrowthRate, CareCostInflationRate float64 }
+		}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Rows) != 2*2*3 {
+		t.Fatalf("got %d rows, want 12", len(got.Rows))
+	}
+	last := got.Rows[len(got.Rows)-1].Rates
+	if last.InvestmentGrowthRate != 5 || last.HousePriceGrowthRate != 2 || last.CareCostInflationRate != 8 {
+		t.Errorf("last row has rates %+v, want the last of each list", last)
+	}
+}